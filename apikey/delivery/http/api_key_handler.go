@@ -0,0 +1,97 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/validation"
+)
+
+const ID = "id"
+
+// CreatedKey is the response to creating or rotating a key, the only time
+// its secret is returned.
+type CreatedKey struct {
+	domain.APIKey
+	Secret string `json:"secret"`
+}
+
+type APIKeyHandler struct {
+	KUseCase  domain.APIKeyUseCase
+	validator *validator.Validate
+}
+
+// NewAPIKeyHandler registers the api key administration routes on e, each of
+// them wrapped in middleware.
+func NewAPIKeyHandler(e *echo.Echo, us domain.APIKeyUseCase, middleware ...echo.MiddlewareFunc) {
+	handler := &APIKeyHandler{KUseCase: us, validator: validation.Validator()}
+	handler.Register(e.Group("/api/v1", middleware...))
+}
+
+func (h *APIKeyHandler) Register(g *echo.Group) {
+	g.GET("/api-keys", h.Fetch)
+	g.POST("/api-keys", h.Create)
+	g.POST("/api-keys/:id/rotate", h.Rotate)
+	g.DELETE("/api-keys/:id", h.Revoke)
+}
+
+func (h APIKeyHandler) Fetch(c echo.Context) error {
+	keys, err := h.KUseCase.Fetch(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, keys)
+}
+
+func (h APIKeyHandler) Create(c echo.Context) error {
+	var key domain.APIKey
+	if err := c.Bind(&key); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(&key); err != nil {
+		return err
+	}
+
+	secret, err := h.KUseCase.Create(c.Request().Context(), &key)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, CreatedKey{APIKey: key, Secret: secret})
+}
+
+func (h APIKeyHandler) Rotate(c echo.Context) error {
+	id, err := keyID(c)
+	if err != nil {
+		return err
+	}
+
+	key, secret, err := h.KUseCase.Rotate(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, CreatedKey{APIKey: key, Secret: secret})
+}
+
+func (h APIKeyHandler) Revoke(c echo.Context) error {
+	id, err := keyID(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.KUseCase.Revoke(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func keyID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param(ID))
+	if err != nil {
+		return 0, &domain.InvalidIDError{Param: ID, Value: c.Param(ID)}
+	}
+	return id, nil
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/validation"
+)
+
+func TestCreate(t *testing.T) {
+	mockUCase := new(mocks.APIKeyUseCase)
+	mockUCase.On("Create", mock.Anything, mock.AnythingOfType("*domain.APIKey")).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.APIKey).ID = 7 }).
+		Return("0a1b2c3d.secret", nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/api-keys", strings.NewReader(`{"name":"importer","scopes":["books:read"]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := APIKeyHandler{KUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, handler.Create(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "0a1b2c3d.secret", body["secret"])
+	assert.EqualValues(t, 7, body["id"])
+	assert.NotContains(t, body, "Hash")
+	mockUCase.AssertExpectations(t)
+}
+
+func TestCreateUnknownScope(t *testing.T) {
+	mockUCase := new(mocks.APIKeyUseCase)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/api-keys", strings.NewReader(`{"name":"importer","scopes":["books:burn"]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := APIKeyHandler{KUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.Create)(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestRevoke(t *testing.T) {
+	mockUCase := new(mocks.APIKeyUseCase)
+	mockUCase.On("Revoke", mock.Anything, 7).Return(nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.DELETE, "/api/v1/api-keys/7", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(ID)
+	c.SetParamValues("7")
+	handler := APIKeyHandler{KUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, handler.Revoke(c))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+)
+
+const selectAPIKey = `SELECT id, name, owner, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at FROM api_key`
+
+type postgresAPIKeyRepository struct {
+	Conn *sql.DB
+}
+
+func NewPostgresAPIKeyRepository(Conn *sql.DB) domain.APIKeyRepository {
+	return &postgresAPIKeyRepository{Conn}
+}
+
+func (p *postgresAPIKeyRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]domain.APIKey, error) {
+	rows, err := p.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make([]domain.APIKey, 0)
+	for rows.Next() {
+		key := domain.APIKey{}
+		err = rows.Scan(
+			&key.ID,
+			&key.Name,
+			&key.Owner,
+			&key.Prefix,
+			&key.Hash,
+			pq.Array(&key.Scopes),
+			&key.ExpiresAt,
+			&key.LastUsedAt,
+			&key.RevokedAt,
+			&key.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, key)
+	}
+	return result, rows.Err()
+}
+
+func (p *postgresAPIKeyRepository) getOne(ctx context.Context, query string, args ...interface{}) (domain.APIKey, error) {
+	list, err := p.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	if len(list) == 0 {
+		return domain.APIKey{}, fmt.Errorf("api key: %w", domain.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+func (p *postgresAPIKeyRepository) Fetch(ctx context.Context) ([]domain.APIKey, error) {
+	return p.fetch(ctx, selectAPIKey+` ORDER BY id`)
+}
+
+func (p *postgresAPIKeyRepository) GetById(ctx context.Context, id int) (domain.APIKey, error) {
+	return p.getOne(ctx, selectAPIKey+` WHERE id = $1`, id)
+}
+
+func (p *postgresAPIKeyRepository) GetByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	return p.getOne(ctx, selectAPIKey+` WHERE key_hash = $1`, hash)
+}
+
+func (p *postgresAPIKeyRepository) Add(ctx context.Context, key *domain.APIKey) error {
+	return p.Conn.QueryRowContext(ctx, `INSERT INTO api_key (name, owner, prefix, key_hash, scopes, expires_at, created_at) `+
+		`VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		key.Name, key.Owner, key.Prefix, key.Hash, pq.Array(key.Scopes), key.ExpiresAt, key.CreatedAt).Scan(&key.ID)
+}
+
+func (p *postgresAPIKeyRepository) UpdateSecret(ctx context.Context, key *domain.APIKey) error {
+	res, err := p.Conn.ExecContext(ctx, `UPDATE api_key SET prefix = $1, key_hash = $2, last_used_at = NULL WHERE id = $3 AND revoked_at IS NULL`,
+		key.Prefix, key.Hash, key.ID)
+	if err != nil {
+		return err
+	}
+	return checkAffected(res, key.ID)
+}
+
+func (p *postgresAPIKeyRepository) Revoke(ctx context.Context, id int, at time.Time) error {
+	res, err := p.Conn.ExecContext(ctx, `UPDATE api_key SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`, at, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(res, id)
+}
+
+func (p *postgresAPIKeyRepository) Touch(ctx context.Context, id int, at time.Time) error {
+	_, err := p.Conn.ExecContext(ctx, `UPDATE api_key SET last_used_at = $1 WHERE id = $2`, at, id)
+	return err
+}
+
+func checkAffected(res sql.Result, id int) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("active api key %d: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+)
+
+func TestGetByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "owner", "prefix", "key_hash", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at"}).
+		AddRow(7, "importer", "admin-1", "0a1b2c3d", "hash", "{books:read,books:write}", nil, nil, nil, now)
+	mock.ExpectQuery(regexp.QuoteMeta(selectAPIKey + ` WHERE key_hash = $1`)).WithArgs("hash").WillReturnRows(rows)
+
+	key, err := NewPostgresAPIKeyRepository(db).GetByHash(context.TODO(), "hash")
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, key.ID)
+	assert.Equal(t, []string{"books:read", "books:write"}, key.Scopes)
+	assert.Nil(t, key.RevokedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByHashNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(selectAPIKey)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = NewPostgresAPIKeyRepository(db).GetByHash(context.TODO(), "hash")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestAddAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	key := &domain.APIKey{Name: "importer", Owner: "admin-1", Prefix: "0a1b2c3d", Hash: "hash", Scopes: []string{"books:read"}, CreatedAt: time.Now()}
+	mock.ExpectQuery("INSERT INTO api_key").
+		WithArgs(key.Name, key.Owner, key.Prefix, key.Hash, pq.Array(key.Scopes), key.ExpiresAt, key.CreatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	require.NoError(t, NewPostgresAPIKeyRepository(db).Add(context.TODO(), key))
+	assert.Equal(t, 7, key.ID)
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	at := time.Now()
+	mock.ExpectExec("UPDATE api_key SET revoked_at").WithArgs(at, 7).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = NewPostgresAPIKeyRepository(db).Revoke(context.TODO(), 7, at)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/validation"
+)
+
+const (
+	prefixLength = 4
+	secretLength = 32
+)
+
+type apiKeyUseCase struct {
+	keyRepo    domain.APIKeyRepository
+	authorizer domain.Authorizer
+	timeouts   timeout.Timeouts
+	validator  *validator.Validate
+	now        func() time.Time
+}
+
+func NewAPIKeyUseCase(r domain.APIKeyRepository, a domain.Authorizer, timeouts timeout.Timeouts) domain.APIKeyUseCase {
+	return &apiKeyUseCase{
+		keyRepo:    r,
+		authorizer: a,
+		timeouts:   timeouts,
+		validator:  validation.Validator(),
+		now:        time.Now,
+	}
+}
+
+func (a *apiKeyUseCase) Fetch(c context.Context) ([]domain.APIKey, error) {
+	if err := a.authorizer.Authorize(c, domain.PermissionManageKeys); err != nil {
+		return nil, err
+	}
+	ctxt, cancel := context.WithTimeout(c, a.timeouts.Of("fetch"))
+	defer cancel()
+
+	return a.keyRepo.Fetch(ctxt)
+}
+
+// Create stores a new key and returns its secret, which can not be recovered
+// later. The owner defaults to the principal creating the key, who must hold
+// every scope given to it.
+func (a *apiKeyUseCase) Create(c context.Context, key *domain.APIKey) (string, error) {
+	if err := a.authorizer.Authorize(c, domain.PermissionManageKeys); err != nil {
+		return "", err
+	}
+	if err := a.validator.Struct(key); err != nil {
+		return "", err
+	}
+	for _, scope := range key.Scopes {
+		if err := a.authorizer.Authorize(c, domain.Permission(scope)); err != nil {
+			return "", fmt.Errorf("api key scope %s: %w", scope, err)
+		}
+	}
+	ctxt, cancel := context.WithTimeout(c, a.timeouts.Of("create"))
+	defer cancel()
+
+	if principal, ok := domain.PrincipalFromContext(c); ok && key.Owner == "" {
+		key.Owner = principal.Subject
+	}
+	secret, err := newSecret(key)
+	if err != nil {
+		return "", err
+	}
+	key.ID = 0
+	key.CreatedAt = a.now().UTC()
+	key.LastUsedAt = nil
+	key.RevokedAt = nil
+
+	if err = a.keyRepo.Add(ctxt, key); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// Rotate replaces the secret of an active key, the previous secret stops
+// working immediately.
+func (a *apiKeyUseCase) Rotate(c context.Context, id int) (domain.APIKey, string, error) {
+	if err := a.authorizer.Authorize(c, domain.PermissionManageKeys); err != nil {
+		return domain.APIKey{}, "", err
+	}
+	ctxt, cancel := context.WithTimeout(c, a.timeouts.Of("rotate"))
+	defer cancel()
+
+	key, err := a.keyRepo.GetById(ctxt, id)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	if key.RevokedAt != nil {
+		return domain.APIKey{}, "", fmt.Errorf("api key %d is revoked: %w", id, domain.ErrNotFound)
+	}
+	secret, err := newSecret(&key)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	key.LastUsedAt = nil
+	if err = a.keyRepo.UpdateSecret(ctxt, &key); err != nil {
+		return domain.APIKey{}, "", err
+	}
+	return key, secret, nil
+}
+
+func (a *apiKeyUseCase) Revoke(c context.Context, id int) error {
+	if err := a.authorizer.Authorize(c, domain.PermissionManageKeys); err != nil {
+		return err
+	}
+	ctxt, cancel := context.WithTimeout(c, a.timeouts.Of("revoke"))
+	defer cancel()
+
+	return a.keyRepo.Revoke(ctxt, id, a.now().UTC())
+}
+
+// Authenticate returns the principal of the active key matching secret and
+// records its use. Keys are looked up in the tenant of c, which the principal
+// is bound to. Every failure wraps domain.ErrUnauthorized.
+func (a *apiKeyUseCase) Authenticate(c context.Context, secret string) (domain.Principal, error) {
+	ctxt, cancel := context.WithTimeout(c, a.timeouts.Of("authenticate"))
+	defer cancel()
+
+	key, err := a.keyRepo.GetByHash(ctxt, hash(secret))
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Principal{}, fmt.Errorf("%w: unknown api key", domain.ErrUnauthorized)
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	now := a.now()
+	if key.RevokedAt != nil {
+		return domain.Principal{}, fmt.Errorf("%w: api key %s is revoked", domain.ErrUnauthorized, key.Prefix)
+	}
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return domain.Principal{}, fmt.Errorf("%w: api key %s is expired", domain.ErrUnauthorized, key.Prefix)
+	}
+	if err = a.keyRepo.Touch(ctxt, key.ID, now.UTC()); err != nil {
+		return domain.Principal{}, err
+	}
+
+	tenantID, _ := domain.TenantFromContext(c)
+	return domain.Principal{
+		Subject: "apikey:" + strconv.Itoa(key.ID),
+		Name:    key.Name,
+		Scopes:  key.Scopes,
+		Tenant:  tenantID,
+	}, nil
+}
+
+// newSecret generates a secret of the form "<prefix>.<random>" for key and
+// stores its prefix and hash in the key.
+func newSecret(key *domain.APIKey) (string, error) {
+	random := make([]byte, prefixLength+secretLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	key.Prefix = hex.EncodeToString(random[:prefixLength])
+	secret := key.Prefix + "." + base64.RawURLEncoding.EncodeToString(random[prefixLength:])
+	key.Hash = hash(secret)
+	return secret, nil
+}
+
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(secret)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/mocks"
+)
+
+func TestCreate(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockAuthorizer := new(mocks.Authorizer)
+	mockAuthorizer.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.APIKey")).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.APIKey).ID = 7 }).
+		Return(nil).Once()
+
+	ctx := domain.NewContextWithPrincipal(context.TODO(), domain.Principal{Subject: "admin-1"})
+	key := domain.APIKey{Name: "importer", Scopes: []string{"books:read"}}
+	secret, err := NewAPIKeyUseCase(mockRepo, mockAuthorizer, timeout.Every(time.Second)).Create(ctx, &key)
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, key.ID)
+	assert.Equal(t, "admin-1", key.Owner)
+	assert.True(t, strings.HasPrefix(secret, key.Prefix+"."))
+	assert.Equal(t, hash(secret), key.Hash)
+	assert.False(t, key.CreatedAt.IsZero())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateValidatesScopes(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockAuthorizer := new(mocks.Authorizer)
+	mockAuthorizer.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.APIKey")).Return(nil).Once()
+	usecase := NewAPIKeyUseCase(mockRepo, mockAuthorizer, timeout.Every(time.Second))
+
+	key := domain.APIKey{Name: "circulation", Scopes: []string{"loans:write", "holds:read"}}
+	_, err := usecase.Create(context.TODO(), &key)
+	require.NoError(t, err)
+
+	key = domain.APIKey{Name: "importer", Scopes: []string{"books:read", "books:burn"}}
+	_, err = usecase.Create(context.TODO(), &key)
+	var validationErrors validator.ValidationErrors
+	require.True(t, errors.As(err, &validationErrors))
+	assert.Equal(t, "permission", validationErrors[0].Tag())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateForbidden(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockAuthorizer := new(mocks.Authorizer)
+	mockAuthorizer.On("Authorize", mock.Anything, domain.PermissionManageKeys).Return(domain.ErrForbidden)
+
+	key := domain.APIKey{Name: "importer", Scopes: []string{"books:read"}}
+	_, err := NewAPIKeyUseCase(mockRepo, mockAuthorizer, timeout.Every(time.Second)).Create(context.TODO(), &key)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
+
+func TestCreateRefusesScopesTheCallerLacks(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockAuthorizer := new(mocks.Authorizer)
+	mockAuthorizer.On("Authorize", mock.Anything, domain.PermissionManageKeys).Return(nil)
+	mockAuthorizer.On("Authorize", mock.Anything, domain.PermissionReadBooks).Return(nil)
+	mockAuthorizer.On("Authorize", mock.Anything, domain.PermissionDeleteBooks).Return(domain.ErrForbidden)
+
+	key := domain.APIKey{Name: "importer", Scopes: []string{"books:read", "books:delete"}}
+	_, err := NewAPIKeyUseCase(mockRepo, mockAuthorizer, timeout.Every(time.Second)).Create(context.TODO(), &key)
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
+
+func TestRotate(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockAuthorizer := new(mocks.Authorizer)
+	mockAuthorizer.On("Authorize", mock.Anything, domain.PermissionManageKeys).Return(nil)
+	mockRepo.On("GetById", mock.Anything, 7).Return(domain.APIKey{ID: 7, Prefix: "00000000", Hash: "old"}, nil).Once()
+	mockRepo.On("UpdateSecret", mock.Anything, mock.AnythingOfType("*domain.APIKey")).Return(nil).Once()
+
+	key, secret, err := NewAPIKeyUseCase(mockRepo, mockAuthorizer, timeout.Every(time.Second)).Rotate(context.TODO(), 7)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, "old", key.Hash)
+	assert.Equal(t, hash(secret), key.Hash)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthenticate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name string
+		key  domain.APIKey
+		err  error
+	}{
+		{name: "active", key: domain.APIKey{ID: 7, Name: "importer", Scopes: []string{"books:read"}, ExpiresAt: &future}},
+		{name: "expired", key: domain.APIKey{ID: 7, ExpiresAt: &past}, err: domain.ErrUnauthorized},
+		{name: "revoked", key: domain.APIKey{ID: 7, RevokedAt: &past}, err: domain.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.APIKeyRepository)
+			mockRepo.On("GetByHash", mock.Anything, hash("secret")).Return(tt.key, nil).Once()
+			mockRepo.On("Touch", mock.Anything, 7, mock.AnythingOfType("time.Time")).Return(nil).Maybe()
+
+			ctx := domain.NewContextWithTenant(context.TODO(), "central")
+			principal, err := NewAPIKeyUseCase(mockRepo, new(mocks.Authorizer), timeout.Every(time.Second)).Authenticate(ctx, "secret")
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				mockRepo.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.Principal{Subject: "apikey:7", Name: "importer", Scopes: []string{"books:read"}, Tenant: "central"}, principal)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthenticateUnknown(t *testing.T) {
+	mockRepo := new(mocks.APIKeyRepository)
+	mockRepo.On("GetByHash", mock.Anything, mock.AnythingOfType("string")).Return(domain.APIKey{}, domain.ErrNotFound).Once()
+
+	_, err := NewAPIKeyUseCase(mockRepo, new(mocks.Authorizer), timeout.Every(time.Second)).Authenticate(context.TODO(), "secret")
+
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.NotErrorIs(t, err, domain.ErrNotFound)
+}
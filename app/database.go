@@ -0,0 +1,50 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/spf13/viper"
+
+	"github.com/bxcodec/library/internal/database"
+	"github.com/bxcodec/library/tenant"
+)
+
+// databaseConfig returns the configured connection to the database of host as
+// user.
+func databaseConfig(host string, port int, user string, pass string) database.Config {
+	return database.Config{
+		Host:             host,
+		Port:             port,
+		User:             user,
+		Password:         pass,
+		Name:             viper.GetString(`database.name`),
+		SSLMode:          viper.GetString(`database.ssl_mode`),
+		TimeZone:         viper.GetString(`database.time_zone`),
+		ConnectTimeout:   viper.GetDuration(`database.connect_timeout`),
+		StatementTimeout: viper.GetDuration(`database.statement_timeout`),
+	}
+}
+
+// databasePool returns the configured pool of every database of the service.
+func databasePool() database.Pool {
+	return database.Pool{
+		MaxOpen:     viper.GetInt(`database.pool.max_open`),
+		MaxIdle:     viper.GetInt(`database.pool.max_idle`),
+		MaxLifetime: viper.GetDuration(`database.pool.max_lifetime`),
+		MaxIdleTime: viper.GetDuration(`database.pool.max_idle_time`),
+	}
+}
+
+// openPostgres opens the database of host as the user of the service, its
+// sessions scoped to the tenant of the context.
+func openPostgres(host string, port int) (*sql.DB, error) {
+	config := databaseConfig(host, port, viper.GetString(`database.user`), viper.GetString(`database.pass`))
+	connector, err := pq.NewConnector(config.DSN())
+	if err != nil {
+		return nil, err
+	}
+	db := sql.OpenDB(tenant.NewConnector(connector))
+	databasePool().Apply(db)
+	return db, nil
+}
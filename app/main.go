@@ -1,25 +1,70 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"expvar"
 	"fmt"
 	"log"
+	nethttp "net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo"
-	_ "github.com/lib/pq"
 	"github.com/spf13/viper"
 
+	_api_key_http "github.com/bxcodec/library/apikey/delivery/http"
+	_api_key_repository "github.com/bxcodec/library/apikey/repository/postgres"
+	_api_key_usecase "github.com/bxcodec/library/apikey/usecase"
+	"github.com/bxcodec/library/auth"
 	"github.com/bxcodec/library/author/repository/postgres"
 	"github.com/bxcodec/library/book/delivery/http"
+	"github.com/bxcodec/library/book/repository/cache"
 	_book_repository "github.com/bxcodec/library/book/repository/postgres"
 	"github.com/bxcodec/library/book/usecase"
+	_branch_http "github.com/bxcodec/library/branch/delivery/http"
+	_branch_repository "github.com/bxcodec/library/branch/repository/postgres"
+	_branch_usecase "github.com/bxcodec/library/branch/usecase"
+	_copy_http "github.com/bxcodec/library/copy/delivery/http"
+	_copy_repository "github.com/bxcodec/library/copy/repository/postgres"
+	_copy_usecase "github.com/bxcodec/library/copy/usecase"
+	"github.com/bxcodec/library/domain"
+	_fine_http "github.com/bxcodec/library/fine/delivery/http"
+	_fine_repository "github.com/bxcodec/library/fine/repository/postgres"
+	_fine_usecase "github.com/bxcodec/library/fine/usecase"
+	_hold_http "github.com/bxcodec/library/hold/delivery/http"
+	_hold_repository "github.com/bxcodec/library/hold/repository/postgres"
+	_hold_usecase "github.com/bxcodec/library/hold/usecase"
+	"github.com/bxcodec/library/i18n"
+	"github.com/bxcodec/library/internal/replica"
+	"github.com/bxcodec/library/internal/timeout"
+	_loan_http "github.com/bxcodec/library/loan/delivery/http"
+	_loan_repository "github.com/bxcodec/library/loan/repository/postgres"
+	_loan_usecase "github.com/bxcodec/library/loan/usecase"
 	"github.com/bxcodec/library/mail"
+	_member_http "github.com/bxcodec/library/member/delivery/http"
+	_member_repository "github.com/bxcodec/library/member/repository/postgres"
+	_member_usecase "github.com/bxcodec/library/member/usecase"
+	mb "github.com/bxcodec/library/message_broker"
 	"github.com/bxcodec/library/message_broker/rabbit"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/rbac"
+	"github.com/bxcodec/library/tenant"
+	"github.com/bxcodec/library/transaction"
+	_transfer_http "github.com/bxcodec/library/transfer/delivery/http"
+	_transfer_repository "github.com/bxcodec/library/transfer/repository/postgres"
+	_transfer_usecase "github.com/bxcodec/library/transfer/usecase"
 )
 
 func init() {
 	viper.SetConfigFile(`config.json`)
+	// Secrets are kept out of the file: LIBRARY_AUTH_SECRET sets auth.secret,
+	// LIBRARY_MIGRATIONS_USER and LIBRARY_MIGRATIONS_PASS the owner of the schema.
+	viper.SetEnvPrefix("library")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err != nil {
 		panic(err)
@@ -27,15 +72,22 @@ func init() {
 }
 
 func main() {
-	dbHost := viper.GetString(`database.host`)
-	dbPort := viper.GetInt(`database.port`)
-	dbUser := viper.GetString(`database.user`)
-	dbPass := viper.GetString(`database.pass`)
-	dbName := viper.GetString(`database.name`)
-	url := fmt.Sprintf("%s://%s:%s@%s:%d/postgres?sslmode=disable", dbName, dbUser, dbPass, dbHost, dbPort)
-
-	dbConn, err := sql.Open(`postgres`, url)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if viper.GetBool(`migrations.on_start`) {
+		if err := runMigrate([]string{"up"}); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if viper.GetString(`database.driver`) == "sqlite" {
+		log.Fatal(runSQLite())
+	}
 
+	dbConn, err := openPostgres(viper.GetString(`database.host`), viper.GetInt(`database.port`))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -49,17 +101,214 @@ func main() {
 		}
 	}()
 
+	var replicaHosts []struct {
+		Host string
+		Port int
+	}
+	if err = viper.UnmarshalKey(`database.replicas`, &replicaHosts); err != nil {
+		log.Fatal(err)
+	}
+	replicas := make(map[string]*sql.DB)
+	for _, r := range replicaHosts {
+		db, err := openPostgres(r.Host, r.Port)
+		if err != nil {
+			log.Fatal(err)
+		}
+		replicas[fmt.Sprintf("%s:%d", r.Host, r.Port)] = db
+	}
+	cluster := replica.New(dbConn, replicas)
+	defer func() {
+		if err := cluster.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+	if len(replicas) > 0 {
+		go cluster.Run(context.Background(), viper.GetDuration(`database.replica_check_interval`))
+	}
+
+	tenants := make(map[string][]string)
+	for id := range viper.GetStringMap(`tenants.list`) {
+		tenants[id] = viper.GetStringSlice(`tenants.list.` + id + `.hosts`)
+	}
+	resolver, err := tenant.NewResolver(tenants)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	e := echo.New()
+	e.Use(i18n.Middleware())
+	e.Use(problem.Middleware())
+	e.Use(tenant.Middleware(resolver))
 
-	authorRepo := postgres.NewPostgresAuthorRepository(dbConn)
-	bookRepo := _book_repository.NewPostgresBookRepository(dbConn)
+	authorRepo := postgres.NewPostgresAuthorRepository(cluster)
+	books := _book_repository.NewPostgresBookRepository(cluster)
+	bookRepo := cache.NewCachedBookRepository(books, viper.GetInt(`cache.books.size`), viper.GetDuration(`cache.books.ttl`),
+		viper.GetDuration(`cache.books.timeout`))
+	go invalidateBooks(bookRepo)
+	expvar.Publish("database", expvar.Func(func() interface{} { return cluster.Stats() }))
+	expvar.Publish("book_cache", expvar.Func(func() interface{} { return bookRepo.Stats() }))
+	if addr := viper.GetString(`debug.addr`); addr != "" {
+		go func() {
+			log.Println(nethttp.ListenAndServe(addr, expvar.Handler()))
+		}()
+	}
+	defer func() {
+		if err := authorRepo.Close(); err != nil {
+			log.Println(err)
+		}
+		if err := books.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
 	rabbitMqService := rabbit.NewRabbitMqService("crud_exchange")
 	mailUseCase := mail.NewSender()
 
-	timeoutContext := time.Second
-	bookUseCase := usecase.NewBookUseCase(bookRepo, authorRepo, rabbitMqService, mailUseCase, timeoutContext)
+	isolation, err := transaction.ParseIsolation(viper.GetString(`database.transactions.isolation`))
+	if err != nil {
+		log.Fatal(err)
+	}
+	unitOfWork := transaction.NewManager(dbConn, func(tx *transaction.Tx) domain.Repositories {
+		return domain.Repositories{
+			Books:   bookRepo.InTx(_book_repository.NewPostgresBookRepositoryTx(tx.Tx), tx.AfterCommit),
+			Authors: postgres.NewPostgresAuthorRepositoryTx(tx.Tx),
+			Loans:   _loan_repository.NewPostgresLoanRepositoryTx(tx.Tx),
+			Fines:   _fine_repository.NewPostgresFineRepositoryTx(tx.Tx),
+			Holds:   _hold_repository.NewPostgresHoldRepositoryTx(tx.Tx),
 
-	http.NewBookHandler(e, bookUseCase)
+			AfterCommit: tx.AfterCommit,
+		}
+	}, transaction.Options{
+		Isolation: isolation,
+		Retries:   viper.GetInt(`database.transactions.retries`),
+		Backoff:   viper.GetDuration(`database.transactions.backoff`),
+	})
+
+	bookUseCase := usecase.NewBookUseCase(bookRepo, authorRepo, unitOfWork, rabbitMqService, mailUseCase, useCaseTimeouts("books"))
+
+	policy, err := rbac.NewPolicy(viper.GetStringMapStringSlice(`rbac.roles`))
+	if err != nil {
+		log.Fatal(err)
+	}
+	copyRepo := _copy_repository.NewPostgresCopyRepository(cluster)
+	bookUseCase = usecase.NewAvailabilityBookUseCase(bookUseCase, copyRepo, useCaseTimeouts("books"))
+	bookUseCase = usecase.NewAuthorizedBookUseCase(bookUseCase, policy)
+	copyUseCase := _copy_usecase.NewAuthorizedCopyUseCase(_copy_usecase.NewCopyUseCase(copyRepo, useCaseTimeouts("copies")), policy)
+	apiKeyRepo := _api_key_repository.NewPostgresAPIKeyRepository(dbConn)
+	apiKeyUseCase := _api_key_usecase.NewAPIKeyUseCase(apiKeyRepo, policy, useCaseTimeouts("api_keys"))
+	memberRepo := _member_repository.NewPostgresMemberRepository(cluster)
+	memberUseCase := _member_usecase.NewAuthorizedMemberUseCase(_member_usecase.NewMemberUseCase(memberRepo, useCaseTimeouts("members")), policy)
+	branchRepo := _branch_repository.NewPostgresBranchRepository(dbConn)
+	branchUseCase := _branch_usecase.NewAuthorizedBranchUseCase(_branch_usecase.NewBranchUseCase(branchRepo, useCaseTimeouts("branches")), policy)
+	transferEvents := rabbit.NewRabbitMqService("transfer_events")
+	holdRepo := _hold_repository.NewPostgresHoldRepository(dbConn)
+	holds := _hold_usecase.NewHoldUseCase(holdRepo, copyRepo, memberRepo, bookRepo, mailUseCase, transferEvents, viper.GetDuration(`holds.pickup_window`), useCaseTimeouts("holds"))
+	holdUseCase := _hold_usecase.NewAuthorizedHoldUseCase(holds, policy)
+	for _, id := range resolver.IDs() {
+		go _hold_usecase.RunExpiry(domain.NewContextWithTenant(context.Background(), id), holds, viper.GetDuration(`holds.expiry_interval`))
+	}
+	transferRepo := _transfer_repository.NewPostgresTransferRepository(dbConn)
+	transferUseCase := _transfer_usecase.NewAuthorizedTransferUseCase(
+		_transfer_usecase.NewTransferUseCase(transferRepo, holds, transferEvents, useCaseTimeouts("transfers")), policy)
+	fineRepo := _fine_repository.NewPostgresFineRepository(dbConn)
+	fines := _fine_usecase.NewFineUseCase(fineRepo, _fine_usecase.Policy{
+		PerDay: domain.Money(viper.GetInt64(`fines.per_day`)),
+		Cap:    domain.Money(viper.GetInt64(`fines.cap`)),
+		Grace:  viper.GetDuration(`fines.grace`),
+	}, useCaseTimeouts("fines"))
+	fineUseCase := _fine_usecase.NewAuthorizedFineUseCase(fines, policy)
+	loanRepo := _loan_repository.NewPostgresLoanRepository(dbConn)
+	loanUseCase := _loan_usecase.NewLoanUseCase(loanRepo, memberRepo, holds, fines, unitOfWork, rabbit.NewRabbitMqService("loan_events"), _loan_usecase.Policy{
+		Period:        viper.GetDuration(`loans.period`),
+		RenewalPeriod: viper.GetDuration(`loans.renewal_period`),
+		MaxRenewals:   viper.GetInt(`loans.max_renewals`),
+		MaxFines:      domain.Money(viper.GetInt64(`fines.max_due`)),
+	}, useCaseTimeouts("loans"))
+	loanUseCase = _loan_usecase.NewAuthorizedLoanUseCase(loanUseCase, policy)
+	overdueJob := _loan_usecase.NewOverdueJob(loanRepo, memberRepo, bookRepo, fines, mailUseCase, viper.GetDuration(`loans.remind_before`), viper.GetDuration(`fines.grace`), useCaseTimeouts("loans"))
+	for _, id := range resolver.IDs() {
+		go _loan_usecase.RunOverdue(domain.NewContextWithTenant(context.Background(), id), overdueJob, viper.GetDuration(`loans.overdue_interval`))
+	}
+
+	middleware, err := authMiddleware(apiKeyUseCase, len(resolver.IDs()) > 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	middleware = append(middleware, tenant.Enforce(resolver)...)
+
+	http.NewBookHandler(e, bookUseCase, viper.GetBool(`http.require_if_match`), middleware...)
+	_api_key_http.NewAPIKeyHandler(e, apiKeyUseCase, middleware...)
+	_member_http.NewMemberHandler(e, memberUseCase, middleware...)
+	_loan_http.NewLoanHandler(e, loanUseCase, middleware...)
+	_copy_http.NewCopyHandler(e, copyUseCase, middleware...)
+	_hold_http.NewHoldHandler(e, holdUseCase, middleware...)
+	_fine_http.NewFineHandler(e, fineUseCase, middleware...)
+	_branch_http.NewBranchHandler(e, branchUseCase, middleware...)
+	_transfer_http.NewTransferHandler(e, transferUseCase, middleware...)
 
 	log.Fatal(e.Start(":9000"))
 }
+
+// invalidateBooks drops the books changed by other instances from the cache,
+// subscribing again when the subscription is lost. Books changed meanwhile stay
+// stale until they expire.
+func invalidateBooks(books *cache.Repository) {
+	events := make(chan mb.Event)
+	go books.Consume(context.Background(), events)
+
+	subscriber := rabbit.NewRabbitMqSubscriber("crud_exchange")
+	patterns := []string{"#." + string(mb.ADD), "#." + string(mb.UPDATE), "#." + string(mb.DELETE)}
+	for {
+		if err := subscriber.Subscribe(context.Background(), patterns, events); err != nil {
+			log.Println(err)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// authMiddleware authenticates the requests when auth is enabled, with api
+// keys when keys is not nil. Callers must be bound to a tenant when multiTenant
+// is set.
+func authMiddleware(keys domain.APIKeyUseCase, multiTenant bool) ([]echo.MiddlewareFunc, error) {
+	if !viper.GetBool(`auth.enabled`) {
+		return nil, nil
+	}
+	if viper.GetString(`auth.secret`) == "" && viper.GetString(`auth.jwks_file`) == "" && viper.GetString(`auth.jwks_url`) == "" {
+		return nil, errors.New("auth.enabled needs the HS256 secret in LIBRARY_AUTH_SECRET, or auth.jwks_file or auth.jwks_url")
+	}
+	verifier, err := auth.NewVerifier(auth.Config{
+		Secret:   viper.GetString(`auth.secret`),
+		JWKSFile: viper.GetString(`auth.jwks_file`),
+		JWKSURL:  viper.GetString(`auth.jwks_url`),
+		Issuer:   viper.GetString(`auth.issuer`),
+		Audience: viper.GetString(`auth.audience`),
+		Leeway:   viper.GetDuration(`auth.leeway`),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []echo.MiddlewareFunc{auth.Middleware(verifier, keys, multiTenant)}, nil
+}
+
+// useCaseTimeouts returns the times the operations of the use case name are
+// given: timeouts.<name>.<operation>, or else timeouts.<name>.default, or
+// timeouts.<name> when it is a duration, or else timeouts.default.
+func useCaseTimeouts(name string) timeout.Timeouts {
+	key := `timeouts.` + name
+	timeouts := timeout.Every(viper.GetDuration(`timeouts.default`))
+	if _, ok := viper.Get(key).(map[string]interface{}); !ok {
+		if viper.IsSet(key) {
+			timeouts.Default = viper.GetDuration(key)
+		}
+		return timeouts
+	}
+
+	timeouts.Operations = make(map[string]time.Duration)
+	for operation := range viper.GetStringMap(key) {
+		if operation == "default" {
+			timeouts.Default = viper.GetDuration(key + `.default`)
+			continue
+		}
+		timeouts.Operations[operation] = viper.GetDuration(key + `.` + operation)
+	}
+	return timeouts
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/viper"
+
+	"github.com/bxcodec/library/migrations"
+)
+
+// openSchema opens the database as the owner of the schema and returns the
+// dialect of its migrations.
+func openSchema() (*sql.DB, migrations.Dialect, error) {
+	if viper.GetString(`database.driver`) == "sqlite" {
+		db, err := openSQLite()
+		return db, migrations.SQLite, err
+	}
+	user, pass := viper.GetString(`migrations.user`), viper.GetString(`migrations.pass`)
+	if user == "" {
+		user, pass = viper.GetString(`database.user`), viper.GetString(`database.pass`)
+	}
+	// Migrations may run longer than the statements of the service.
+	config := databaseConfig(viper.GetString(`database.host`), viper.GetInt(`database.port`), user, pass)
+	config.StatementTimeout = 0
+	db, err := sql.Open(`postgres`, config.DSN())
+	return db, migrations.Postgres, err
+}
+
+// runMigrate runs the migrate command as the owner of the schema, which is
+// configured apart from the user the service connects as:
+//
+//	migrate up              applies the pending migrations
+//	migrate down [n]        reverts the last n migrations, one by default
+//	migrate status          lists the migrations and when they were applied
+//	migrate seed            loads the sample data
+//	migrate baseline [v]    records the migrations up to v, 1 by default, as
+//	                        applied without running them
+func runMigrate(args []string) error {
+	db, dialect, err := openSchema()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db, dialect)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	switch command {
+	case "up":
+		_, err = migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("migrate down: invalid number of steps %q", args[1])
+			}
+		}
+		_, err = migrator.Down(ctx, steps)
+	case "status":
+		var list []migrations.Status
+		if list, err = migrator.Status(ctx); err != nil {
+			return err
+		}
+		for _, status := range list {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+	case "seed":
+		err = migrator.Seed(ctx)
+	case "baseline":
+		version := 1
+		if len(args) > 1 {
+			if version, err = strconv.Atoi(args[1]); err != nil {
+				return fmt.Errorf("migrate baseline: invalid version %q", args[1])
+			}
+		}
+		_, err = migrator.Baseline(ctx, version)
+	default:
+		return fmt.Errorf("migrate: unknown command %q, expected up, down, status, seed or baseline", command)
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/labstack/echo"
+	"github.com/spf13/viper"
+	_ "modernc.org/sqlite"
+
+	_author_sqlite "github.com/bxcodec/library/author/repository/sqlite"
+	"github.com/bxcodec/library/book/delivery/http"
+	_book_sqlite "github.com/bxcodec/library/book/repository/sqlite"
+	"github.com/bxcodec/library/book/usecase"
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/i18n"
+	"github.com/bxcodec/library/mail"
+	"github.com/bxcodec/library/message_broker/rabbit"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/rbac"
+	"github.com/bxcodec/library/transaction"
+)
+
+// openSQLite opens the database file of database.path with foreign keys
+// enforced, readers running beside the writer and writers waiting for each
+// other rather than failing.
+func openSQLite() (*sql.DB, error) {
+	return sql.Open("sqlite", "file:"+viper.GetString(`database.path`)+
+		"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+}
+
+// runSQLite serves the catalogue, the books and their authors, from a SQLite
+// database for deployments without a Postgres server. Such a database belongs
+// to a single library, members, loans and the other features need Postgres.
+func runSQLite() error {
+	db, err := openSQLite()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+	if err = db.Ping(); err != nil {
+		return err
+	}
+
+	authorRepo := _author_sqlite.NewSQLiteAuthorRepository(db)
+	bookRepo := _book_sqlite.NewSQLiteBookRepository(db)
+	defer func() {
+		if err := authorRepo.Close(); err != nil {
+			log.Println(err)
+		}
+		if err := bookRepo.Close(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	policy, err := rbac.NewPolicy(viper.GetStringMapStringSlice(`rbac.roles`))
+	if err != nil {
+		return err
+	}
+	bookUseCase := usecase.NewBookUseCase(bookRepo, authorRepo,
+		transaction.None(domain.Repositories{Books: bookRepo, Authors: authorRepo}), rabbit.NewRabbitMqService("crud_exchange"), mail.NewSender(), useCaseTimeouts("books"))
+	bookUseCase = usecase.NewAuthorizedBookUseCase(bookUseCase, policy)
+
+	middleware, err := authMiddleware(nil, false)
+	if err != nil {
+		return err
+	}
+
+	e := echo.New()
+	e.Use(i18n.Middleware())
+	e.Use(problem.Middleware())
+	http.NewBookHandler(e, bookUseCase, viper.GetBool(`http.require_if_match`), middleware...)
+	return e.Start(":9000")
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval limits how often a remote key set is fetched again when
+// a token is signed with an unknown key.
+const minRefreshInterval = time.Minute
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// KeySet holds the RSA public keys of a JSON Web Key Set (RFC 7517)
+type KeySet struct {
+	load      func() ([]byte, error)
+	refresh   bool
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewFileKeySet reads the key set from a local file once.
+func NewFileKeySet(path string) (*KeySet, error) {
+	s := &KeySet{load: func() ([]byte, error) { return ioutil.ReadFile(path) }}
+	return s, s.fetch()
+}
+
+// NewURLKeySet downloads the key set and downloads it again when a token
+// refers to a key it does not contain, so rotated keys are picked up.
+func NewURLKeySet(url string) (*KeySet, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	s := &KeySet{refresh: true, load: func() ([]byte, error) {
+		res, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("auth: fetching %s: %s", url, res.Status)
+		}
+		return ioutil.ReadAll(res.Body)
+	}}
+	return s, s.fetch()
+}
+
+// Key returns the key identified by kid. An empty kid is accepted when the
+// set holds exactly one key.
+func (s *KeySet) Key(kid string) (*rsa.PublicKey, error) {
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	s.mu.RLock()
+	stale := s.refresh && time.Since(s.fetchedAt) > minRefreshInterval
+	s.mu.RUnlock()
+	if stale {
+		if err := s.fetch(); err != nil {
+			return nil, err
+		}
+		if key, ok := s.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (s *KeySet) lookup(kid string) (*rsa.PublicKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (s *KeySet) fetch() error {
+	raw, err := s.load()
+	if err != nil {
+		return err
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err = json.Unmarshal(raw, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := jwk.rsaPublicKey()
+		if err != nil {
+			return err
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return errors.New("auth: key set has no RSA signing keys")
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("auth: key %q: %v", k.Kid, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("auth: key %q: %v", k.Kid, err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/library/domain"
+)
+
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+)
+
+// Config describes how access tokens are verified. HS256 tokens are accepted
+// when Secret is set, RS256 tokens when JWKSFile or JWKSURL is set.
+type Config struct {
+	Secret   string
+	JWKSFile string
+	JWKSURL  string
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+// Claims are the claims of an access token the service relies on
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	IssuedAt  int64    `json:"iat"`
+	Name      string   `json:"name"`
+	Roles     []string `json:"roles"`
+	Tenant    string   `json:"tenant"`
+}
+
+// Principal returns the caller the claims were issued to.
+func (c Claims) Principal() domain.Principal {
+	return domain.Principal{Subject: c.Subject, Name: c.Name, Roles: c.Roles, Tenant: c.Tenant}
+}
+
+// audience is the "aud" claim, which is either a string or an array of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a audience) contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+	return false
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// minSecretLength is the length of the HS256 hash, which RFC 7518 requires the
+// secret to be at least. It also rules out placeholders such as "change-me".
+const minSecretLength = 32
+
+// Verifier checks the signature and the claims of JWT access tokens
+type Verifier struct {
+	secret   []byte
+	keys     *KeySet
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+func NewVerifier(cfg Config) (*Verifier, error) {
+	v := &Verifier{
+		secret:   []byte(cfg.Secret),
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+
+	var err error
+	switch {
+	case cfg.JWKSFile != "":
+		v.keys, err = NewFileKeySet(cfg.JWKSFile)
+	case cfg.JWKSURL != "":
+		v.keys, err = NewURLKeySet(cfg.JWKSURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(v.secret) == 0 && v.keys == nil {
+		return nil, errors.New("auth: either a secret or a JWKS source is required")
+	}
+	if len(v.secret) > 0 && len(v.secret) < minSecretLength {
+		return nil, fmt.Errorf("auth: the secret must be a random value of at least %d bytes", minSecretLength)
+	}
+	return v, nil
+}
+
+// Verify returns the claims of token when its signature and claims are valid.
+// Every failure wraps domain.ErrUnauthorized.
+func (v *Verifier) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, unauthorized("malformed token")
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return Claims{}, unauthorized("malformed token header")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, unauthorized("malformed token signature")
+	}
+	if err = v.verifySignature(h, parts[0]+"."+parts[1], signature); err != nil {
+		return Claims{}, err
+	}
+
+	var claims Claims
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return Claims{}, unauthorized("malformed token claims")
+	}
+	return claims, v.validate(claims)
+}
+
+func (v *Verifier) verifySignature(h header, signingInput string, signature []byte) error {
+	switch h.Alg {
+	case HS256:
+		if len(v.secret) == 0 {
+			return unauthorized("HS256 tokens are not accepted")
+		}
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return unauthorized("invalid token signature")
+		}
+	case RS256:
+		if v.keys == nil {
+			return unauthorized("RS256 tokens are not accepted")
+		}
+		key, err := v.keys.Key(h.Kid)
+		if err != nil {
+			return unauthorized(err.Error())
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+			return unauthorized("invalid token signature")
+		}
+	default:
+		return unauthorized(fmt.Sprintf("unsupported signing algorithm %q", h.Alg))
+	}
+	return nil
+}
+
+func (v *Verifier) validate(c Claims) error {
+	now := v.now()
+	if c.Subject == "" {
+		return unauthorized("token has no subject")
+	}
+	if c.ExpiresAt == 0 || now.After(time.Unix(c.ExpiresAt, 0).Add(v.leeway)) {
+		return unauthorized("token is expired")
+	}
+	if c.NotBefore != 0 && now.Add(v.leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return unauthorized("token is not valid yet")
+	}
+	if v.issuer != "" && c.Issuer != v.issuer {
+		return unauthorized("token issuer is not trusted")
+	}
+	if v.audience != "" && !c.Audience.contains(v.audience) {
+		return unauthorized("token is issued for another audience")
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func unauthorized(reason string) error {
+	return fmt.Errorf("%w: %s", domain.ErrUnauthorized, reason)
+}
@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/rbac"
+)
+
+func sign(t *testing.T, alg string, kid string, claims map[string]interface{}, key interface{}) string {
+	h, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": kid})
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+	input := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(input))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "user-1",
+		"name":  "Jane",
+		"aud":   []string{"library"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"editor"},
+	}
+}
+
+func TestVerifyHS256(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	v, err := NewVerifier(Config{Secret: string(secret), Audience: "library"})
+	require.NoError(t, err)
+
+	claims, err := v.Verify(sign(t, HS256, "", validClaims(), secret))
+	require.NoError(t, err)
+	assert.Equal(t, domain.Principal{Subject: "user-1", Name: "Jane", Roles: []string{"editor"}}, claims.Principal())
+
+	_, err = v.Verify(sign(t, HS256, "", validClaims(), []byte("other")))
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = v.Verify(sign(t, HS256, "", expired, secret))
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+
+	foreign := validClaims()
+	foreign["aud"] = "shop"
+	_, err = v.Verify(sign(t, HS256, "", foreign, secret))
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+
+	_, err = v.Verify("not.a.token")
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
+
+func TestVerifyRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "k1",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	require.NoError(t, err)
+	dir, err := ioutil.TempDir("", "jwks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jwks.json")
+	require.NoError(t, ioutil.WriteFile(path, jwks, 0600))
+
+	v, err := NewVerifier(Config{JWKSFile: path})
+	require.NoError(t, err)
+
+	claims, err := v.Verify(sign(t, RS256, "k1", validClaims(), key))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.Subject)
+
+	_, err = v.Verify(sign(t, RS256, "k2", validClaims(), key))
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+
+	_, err = v.Verify(sign(t, HS256, "", validClaims(), []byte("0123456789abcdef0123456789abcdef")))
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
+
+func TestVerifierRefusesWeakSecrets(t *testing.T) {
+	for _, secret := range []string{"", "change-me", "0123456789abcdef0123456789abcde"} {
+		_, err := NewVerifier(Config{Secret: secret})
+		assert.Error(t, err, secret)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	v, err := NewVerifier(Config{Secret: string(secret)})
+	require.NoError(t, err)
+
+	var principal domain.Principal
+	next := func(c echo.Context) error {
+		principal, _ = domain.PrincipalFromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+sign(t, HS256, "", validClaims(), secret))
+	rec := httptest.NewRecorder()
+	require.NoError(t, Middleware(v, nil, false)(next)(e.NewContext(req, rec)))
+	assert.Equal(t, "user-1", principal.Subject)
+
+	req = httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Basic dXNlcjpwYXNz")
+	rec = httptest.NewRecorder()
+	err = Middleware(v, nil, false)(next)(e.NewContext(req, rec))
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.Equal(t, "Bearer", rec.Header().Get(echo.HeaderWWWAuthenticate))
+}
+
+func TestMiddlewareAnonymous(t *testing.T) {
+	v, err := NewVerifier(Config{Secret: "0123456789abcdef0123456789abcdef"})
+	require.NoError(t, err)
+	policy, err := rbac.NewPolicy(map[string][]string{rbac.Anonymous: {string(domain.PermissionReadBooks)}})
+	require.NoError(t, err)
+	next := func(permission domain.Permission) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err := policy.Authorize(c.Request().Context(), permission); err != nil {
+				return err
+			}
+			return c.NoContent(http.StatusNoContent)
+		}
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, Middleware(v, nil, false)(next(domain.PermissionReadBooks))(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	req = httptest.NewRequest(echo.DELETE, "/api/v1/books/1", nil)
+	rec = httptest.NewRecorder()
+	err = Middleware(v, nil, false)(next(domain.PermissionDeleteBooks))(e.NewContext(req, rec))
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	assert.Equal(t, "Bearer", rec.Header().Get(echo.HeaderWWWAuthenticate))
+}
+
+func TestMiddlewareTenant(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	v, err := NewVerifier(Config{Secret: string(secret)})
+	require.NoError(t, err)
+	claims := validClaims()
+	claims["tenant"] = "central"
+	token := sign(t, HS256, "", claims, secret)
+
+	var tenantID string
+	next := func(c echo.Context) error {
+		tenantID, _ = domain.TenantFromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	require.NoError(t, Middleware(v, nil, false)(next)(e.NewContext(req, httptest.NewRecorder())))
+	assert.Equal(t, "central", tenantID)
+
+	req = httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req = req.WithContext(domain.NewContextWithTenant(req.Context(), "east"))
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	err = Middleware(v, nil, false)(next)(e.NewContext(req, httptest.NewRecorder()))
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	req = httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req = req.WithContext(domain.NewContextWithTenant(req.Context(), "east"))
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+sign(t, HS256, "", validClaims(), secret))
+	require.NoError(t, Middleware(v, nil, false)(next)(e.NewContext(req, httptest.NewRecorder())))
+	err = Middleware(v, nil, true)(next)(e.NewContext(req, httptest.NewRecorder()))
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestMiddlewareAPIKey(t *testing.T) {
+	keys := new(mocks.APIKeyUseCase)
+	keys.On("Authenticate", mock.Anything, "good").Return(domain.Principal{Subject: "apikey:7", Scopes: []string{"books:read"}}, nil)
+	keys.On("Authenticate", mock.Anything, "bad").Return(domain.Principal{}, domain.ErrUnauthorized)
+
+	var principal domain.Principal
+	next := func(c echo.Context) error {
+		principal, _ = domain.PrincipalFromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req.Header.Set(HeaderAPIKey, "good")
+	require.NoError(t, Middleware(nil, keys, false)(next)(e.NewContext(req, httptest.NewRecorder())))
+	assert.Equal(t, "apikey:7", principal.Subject)
+
+	req = httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req.Header.Set(HeaderAPIKey, "bad")
+	err := Middleware(nil, keys, false)(next)(e.NewContext(req, httptest.NewRecorder()))
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+}
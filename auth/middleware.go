@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/labstack/echo"
+
+	"github.com/bxcodec/library/domain"
+)
+
+const (
+	bearerPrefix = "Bearer "
+
+	// HeaderAPIKey carries the secret of an api key.
+	HeaderAPIKey = "X-API-Key"
+)
+
+// Middleware authenticates every request with the api key of its X-API-Key
+// header or the bearer token of its Authorization header and stores the caller
+// in the request context. Either v or keys may be nil to disable that scheme.
+// A token bound to a tenant sets the tenant of requests not resolved from their
+// host or header and is rejected for requests of another tenant. When
+// multiTenant is set, callers bound to no tenant are rejected, as the tenant of
+// their requests would be chosen by the requests themselves. Requests carrying
+// neither are passed on without a caller, to the anonymous role, and answered
+// 401 when it is not allowed what they ask for.
+func Middleware(v *Verifier, keys domain.APIKeyUseCase, multiTenant bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			secret := c.Request().Header.Get(HeaderAPIKey)
+			if header == "" && secret == "" {
+				err := next(c)
+				if errors.Is(err, domain.ErrForbidden) {
+					c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+					return unauthorized("bearer token or api key is missing")
+				}
+				return err
+			}
+
+			var principal domain.Principal
+			if keys != nil && secret != "" {
+				var err error
+				if principal, err = keys.Authenticate(c.Request().Context(), secret); err != nil {
+					return err
+				}
+			} else {
+				if v == nil || !strings.HasPrefix(header, bearerPrefix) {
+					c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+					return unauthorized("bearer token or api key is missing")
+				}
+
+				claims, err := v.Verify(strings.TrimSpace(header[len(bearerPrefix):]))
+				if err != nil {
+					c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+					return err
+				}
+				principal = claims.Principal()
+			}
+
+			ctx := c.Request().Context()
+			tenantID, resolved := domain.TenantFromContext(ctx)
+			switch {
+			case principal.Tenant == "" && multiTenant:
+				return fmt.Errorf("%s is bound to no tenant: %w", principal.Subject, domain.ErrForbidden)
+			case principal.Tenant == "":
+				principal.Tenant = tenantID
+			case !resolved:
+				ctx = domain.NewContextWithTenant(ctx, principal.Tenant)
+			case principal.Tenant != tenantID:
+				return fmt.Errorf("%s may not access tenant %q: %w", principal.Subject, tenantID, domain.ErrForbidden)
+			}
+
+			ctx = domain.NewContextWithPrincipal(ctx, principal)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
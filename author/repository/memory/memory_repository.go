@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bxcodec/library/domain"
+)
+
+type memoryAuthorRepo struct {
+	authors map[int]domain.Author
+}
+
+// NewMemoryAuthorRepository returns a repository keeping authors in memory.
+// The authors are fixed once created, so it is safe for concurrent use.
+func NewMemoryAuthorRepository(authors ...domain.Author) domain.AuthorRepository {
+	m := &memoryAuthorRepo{authors: make(map[int]domain.Author, len(authors))}
+	for _, author := range authors {
+		m.authors[author.ID] = author
+	}
+	return m
+}
+
+func (m *memoryAuthorRepo) GetById(ctx context.Context, id int) (domain.Author, error) {
+	author, ok := m.authors[id]
+	if !ok {
+		return domain.Author{}, fmt.Errorf("author %d: %w", id, domain.ErrNotFound)
+	}
+	return author, nil
+}
+
+func (m *memoryAuthorRepo) FetchByIDs(ctx context.Context, ids []int) (map[int]domain.Author, error) {
+	result := make(map[int]domain.Author, len(ids))
+	for _, id := range ids {
+		if author, ok := m.authors[id]; ok {
+			result[id] = author
+		}
+	}
+	return result, nil
+}
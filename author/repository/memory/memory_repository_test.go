@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.AuthorRepository(t, func(t *testing.T, names ...string) domain.AuthorRepository {
+		now := time.Now().UTC()
+		authors := make([]domain.Author, len(names))
+		for i, name := range names {
+			authors[i] = domain.Author{ID: i + 1, Name: name, CreatedAt: now, UpdatedAt: now}
+		}
+		return NewMemoryAuthorRepository(authors...)
+	})
+}
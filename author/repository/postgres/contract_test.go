@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/replica"
+	"github.com/bxcodec/library/internal/repotest"
+)
+
+// TestContract runs against the database of repotest.EnvPostgres and is
+// skipped without one.
+func TestContract(t *testing.T) {
+	repotest.AuthorRepository(t, func(t *testing.T, authors ...string) domain.AuthorRepository {
+		repo := NewPostgresAuthorRepository(replica.New(repotest.Postgres(t, authors...), nil))
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
@@ -5,37 +5,119 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/query"
+	"github.com/bxcodec/library/internal/replica"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
 )
 
-type postgresAuthorRepo struct {
-	DB *sql.DB
+// PostgresAuthorRepository is the domain.AuthorRepository of a Postgres
+// database.
+type PostgresAuthorRepository struct {
+	DB    *replica.Cluster
+	tx    *sql.Tx
+	stmts *query.Statements
 }
 
-func NewPostgresAuthorRepository(db *sql.DB) domain.AuthorRepository {
-	return &postgresAuthorRepo{
-		DB: db,
+// NewPostgresAuthorRepository returns the authors of db, read from its
+// replicas.
+func NewPostgresAuthorRepository(db *replica.Cluster) *PostgresAuthorRepository {
+	return &PostgresAuthorRepository{
+		DB:    db,
+		stmts: query.NewStatements(),
 	}
 }
 
-func (p postgresAuthorRepo) GetById(ctx context.Context, id int) (domain.Author, error) {
-	query := fmt.Sprintf("SELECT id, name, created_at, updated_at FROM author WHERE id=%d", id)
-	return p.getOne(ctx, query)
+// NewPostgresAuthorRepositoryTx returns the authors of tx, read in it by a unit
+// of work.
+func NewPostgresAuthorRepositoryTx(tx *sql.Tx) domain.AuthorRepository {
+	return &PostgresAuthorRepository{tx: tx}
 }
 
-func (p postgresAuthorRepo) getOne(ctx context.Context, query string) (res domain.Author, err error) {
-	stmt, err := p.DB.PrepareContext(ctx, query)
+func selectAuthors() *query.Builder {
+	return query.Select("id", "name", "created_at", "updated_at").From("author")
+}
+
+func (p *PostgresAuthorRepository) GetById(ctx context.Context, id int) (domain.Author, error) {
+	list, err := p.fetch(ctx, selectAuthors().Where("id = ?", id))
 	if err != nil {
 		return domain.Author{}, err
 	}
+	if len(list) == 0 {
+		return domain.Author{}, fmt.Errorf("author %d: %w", id, domain.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+// FetchByIDs loads the authors of ids with a single query.
+func (p *PostgresAuthorRepository) FetchByIDs(ctx context.Context, ids []int) (map[int]domain.Author, error) {
+	result := make(map[int]domain.Author, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	list, err := p.fetch(ctx, selectAuthors().Where("id = ANY(?)", pq.Array(ids)))
+	if err != nil {
+		return nil, err
+	}
+	for _, author := range list {
+		result[author.ID] = author
+	}
+	return result, nil
+}
+
+// Close releases the statements prepared by the repository.
+func (p *PostgresAuthorRepository) Close() error {
+	if p.stmts == nil {
+		return nil
+	}
+	return p.stmts.Close()
+}
+
+func (p *PostgresAuthorRepository) fetch(ctx context.Context, b *query.Builder) ([]domain.Author, error) {
+	text, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := p.read(ctx, text, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
 
-	row := stmt.QueryRow()
-	res = domain.Author{}
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
 
-	err = row.Scan(
-		&res.ID,
-		&res.Name,
-		&res.CreatedAt,
-		&res.UpdatedAt,
-	)
-	return
+	result := make([]domain.Author, 0)
+	for rows.Next() {
+		author := domain.Author{}
+		err = rows.Scan(
+			&author.ID,
+			&author.Name,
+			&author.CreatedAt,
+			&author.UpdatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, author)
+	}
+	return result, rows.Err()
+}
+
+// read runs the statement text on the transaction of the repository, if any,
+// or prepared on a reader of ctx.
+func (p *PostgresAuthorRepository) read(ctx context.Context, text string, args ...interface{}) (*sql.Rows, error) {
+	if p.tx != nil {
+		return p.tx.QueryContext(ctx, text, args...)
+	}
+	stmt, err := p.stmts.Prepare(ctx, p.DB.Reader(ctx), text)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
 }
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/replica"
+)
+
+func TestGetById(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(`SELECT id, name, created_at, updated_at FROM author WHERE id = $1`)).
+		ExpectQuery().WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).AddRow(1, "Iman Tumorang", now, now))
+
+	author, err := NewPostgresAuthorRepository(replica.New(db, nil)).GetById(context.TODO(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.Author{ID: 1, Name: "Iman Tumorang", CreatedAt: now, UpdatedAt: now}, author)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByIdNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(`SELECT id, name, created_at, updated_at FROM author WHERE id = $1`)).
+		ExpectQuery().WithArgs(9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}))
+
+	_, err = NewPostgresAuthorRepository(replica.New(db, nil)).GetById(context.TODO(), 9)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectPrepare(regexp.QuoteMeta(`SELECT id, name, created_at, updated_at FROM author WHERE id = ANY($1)`)).
+		ExpectQuery().WithArgs(pq.Array([]int{1, 2})).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+			AddRow(1, "Iman Tumorang", now, now).
+			AddRow(2, "Arthur Conan Doyle", now, now))
+
+	authors, err := NewPostgresAuthorRepository(replica.New(db, nil)).FetchByIDs(context.TODO(), []int{1, 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[int]domain.Author{
+		1: {ID: 1, Name: "Iman Tumorang", CreatedAt: now, UpdatedAt: now},
+		2: {ID: 2, Name: "Arthur Conan Doyle", CreatedAt: now, UpdatedAt: now},
+	}, authors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchByIDsEmpty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	authors, err := NewPostgresAuthorRepository(replica.New(db, nil)).FetchByIDs(context.TODO(), nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, authors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
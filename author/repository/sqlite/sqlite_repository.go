@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/query"
+	"github.com/sirupsen/logrus"
+)
+
+// SQLiteAuthorRepository is the domain.AuthorRepository of a SQLite database.
+type SQLiteAuthorRepository struct {
+	DB    *sql.DB
+	stmts *query.Statements
+}
+
+// NewSQLiteAuthorRepository returns the authors stored in the SQLite database
+// of db, migrated with migrations.SQLite.
+func NewSQLiteAuthorRepository(db *sql.DB) *SQLiteAuthorRepository {
+	return &SQLiteAuthorRepository{
+		DB:    db,
+		stmts: query.NewStatements(),
+	}
+}
+
+func selectAuthors() *query.Builder {
+	return query.Select("id", "name", "created_at", "updated_at").From("author")
+}
+
+func (s *SQLiteAuthorRepository) GetById(ctx context.Context, id int) (domain.Author, error) {
+	list, err := s.fetch(ctx, selectAuthors().Where("id = ?", id))
+	if err != nil {
+		return domain.Author{}, err
+	}
+	if len(list) == 0 {
+		return domain.Author{}, fmt.Errorf("author %d: %w", id, domain.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+// FetchByIDs loads the authors of ids with a single query. SQLite has no
+// arrays, the ids are bound one by one.
+func (s *SQLiteAuthorRepository) FetchByIDs(ctx context.Context, ids []int) (map[int]domain.Author, error) {
+	result := make(map[int]domain.Author, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	list, err := s.fetch(ctx, selectAuthors().Where("id IN ("+placeholders+")", args...))
+	if err != nil {
+		return nil, err
+	}
+	for _, author := range list {
+		result[author.ID] = author
+	}
+	return result, nil
+}
+
+// Close releases the statements prepared by the repository.
+func (s *SQLiteAuthorRepository) Close() error {
+	return s.stmts.Close()
+}
+
+func (s *SQLiteAuthorRepository) fetch(ctx context.Context, b *query.Builder) ([]domain.Author, error) {
+	text, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := s.stmts.Prepare(ctx, s.DB, text)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make([]domain.Author, 0)
+	for rows.Next() {
+		author := domain.Author{}
+		err = rows.Scan(
+			&author.ID,
+			&author.Name,
+			&author.CreatedAt,
+			&author.UpdatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, author)
+	}
+	return result, rows.Err()
+}
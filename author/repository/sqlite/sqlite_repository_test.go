@@ -0,0 +1,16 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.AuthorRepository(t, func(t *testing.T, authors ...string) domain.AuthorRepository {
+		repo := NewSQLiteAuthorRepository(repotest.SQLite(t, authors...))
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
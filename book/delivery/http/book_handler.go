@@ -1,37 +1,126 @@
 package http
 
 import (
+	"context"
+	"fmt"
 	"gopkg.in/go-playground/validator.v9"
+	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/validation"
 	"github.com/labstack/echo"
-	"github.com/sirupsen/logrus"
 )
 
 const (
-	NUM    = "num"
-	OFFSET = "offset"
-	ID     = "id"
+	NUM       = "num"
+	OFFSET    = "offset"
+	ID        = "id"
+	BRANCH_ID = "branch_id"
+
+	HeaderETag        = "ETag"
+	HeaderIfMatch     = "If-Match"
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+	HeaderLink        = "Link"
+)
+
+var (
+	ErrPreconditionRequired = echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header is required")
+	ErrUnsupportedPatch     = echo.NewHTTPError(http.StatusUnsupportedMediaType, "patch must be "+string(domain.MergePatch)+" or "+string(domain.JSONPatch))
 )
 
-type ResponseError struct {
-	Message string `json:"message"`
+// LegacySunset is the date after which the unversioned routes are removed.
+const LegacySunset = "Wed, 30 Jun 2027 00:00:00 GMT"
+
+// Envelope wraps every successful response body of the v2 API, errors are
+// reported as problem+json in every version.
+type Envelope struct {
+	Data interface{} `json:"data"`
 }
 
 type BookHandler struct {
-	BUseCase  domain.BookUseCase
-	validator *validator.Validate
+	BUseCase       domain.BookUseCase
+	RequireIfMatch bool
+	validator      *validator.Validate
+	envelope       bool
+}
+
+// NewBookHandler registers the book routes of every API version on e, each of
+// them wrapped in middleware. The unversioned routes are kept as deprecated
+// aliases of /api/v1.
+func NewBookHandler(e *echo.Echo, us domain.BookUseCase, requireIfMatch bool, middleware ...echo.MiddlewareFunc) {
+	handler := &BookHandler{BUseCase: us, RequireIfMatch: requireIfMatch, validator: validation.Validator()}
+	handler.RegisterV1(e.Group("/api/v1", middleware...))
+	handler.RegisterV2(e.Group("/api/v2", middleware...))
+	handler.registerLegacy(e, middleware...)
+}
+
+// RegisterV1 registers the book routes answering with plain JSON bodies.
+func (h *BookHandler) RegisterV1(g *echo.Group) {
+	g.GET("/books", h.FetchBook)
+	g.GET("/books/:id", h.GetById)
+	g.POST("/books", h.Add)
+	g.PUT("/books/:id", h.Update)
+	g.PATCH("/books/:id", h.Patch)
+	g.DELETE("/books/:id", h.Delete)
+}
+
+// RegisterV2 registers the book routes answering with bodies wrapped in an
+// Envelope.
+func (h *BookHandler) RegisterV2(g *echo.Group) {
+	v2 := *h
+	v2.envelope = true
+	g.GET("/books", v2.FetchBook)
+	g.GET("/books/:id", v2.GetById)
+	g.POST("/books", v2.Add)
+	g.PUT("/books/:id", v2.Update)
+	g.PATCH("/books/:id", v2.Patch)
+	g.DELETE("/books/:id", v2.Delete)
 }
 
-func NewBookHandler(e *echo.Echo, us domain.BookUseCase) {
-	handler := &BookHandler{BUseCase: us, validator: validator.New()}
-	e.GET("/books", handler.FetchBook)
-	e.GET("/books/:id", handler.GetById)
-	e.POST("/books", handler.Add)
-	e.PUT("/book", handler.Update)
-	e.DELETE("/books/:id", handler.Delete)
+func (h *BookHandler) registerLegacy(e *echo.Echo, middleware ...echo.MiddlewareFunc) {
+	legacy := func(successor string) []echo.MiddlewareFunc {
+		return append([]echo.MiddlewareFunc{deprecated(successor)}, middleware...)
+	}
+	e.GET("/books", h.FetchBook, legacy("/api/v1/books")...)
+	e.GET("/books/:id", h.GetById, legacy("/api/v1/books/:id")...)
+	e.POST("/books", h.Add, legacy("/api/v1/books")...)
+	e.PUT("/book", h.Update, legacy("/api/v1/books/:id")...)
+	e.PATCH("/books/:id", h.Patch, legacy("/api/v1/books/:id")...)
+	e.DELETE("/books/:id", h.Delete, legacy("/api/v1/books/:id")...)
+}
+
+// deprecated marks the response of a legacy route with the Deprecation and
+// Sunset headers and links to the route replacing it. The :id of successor is
+// the id of the book the request names in its path or, for PUT /book, in its
+// body; the link is left out when the request names no book.
+func deprecated(successor string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set(HeaderDeprecation, "true")
+			header.Set(HeaderSunset, LegacySunset)
+			c.Response().Before(func() {
+				link := successor
+				if strings.Contains(link, ":"+ID) {
+					id := c.Param(ID)
+					if id == "" {
+						id, _ = c.Get(ID).(string)
+					}
+					if id == "" {
+						return
+					}
+					link = strings.Replace(link, ":"+ID, url.PathEscape(id), 1)
+				}
+				header.Set(HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, link))
+			})
+			return next(c)
+		}
+	}
 }
 
 func (h BookHandler) FetchBook(c echo.Context) error {
@@ -39,94 +128,180 @@ func (h BookHandler) FetchBook(c echo.Context) error {
 	num, _ := strconv.Atoi(nums)
 	offsets := c.QueryParam(OFFSET)
 	offset, _ := strconv.Atoi(offsets)
+	ctx, err := branchContext(c)
+	if err != nil {
+		return err
+	}
 
-	listBooks, err := h.BUseCase.Fetch(c.Request().Context(), num, offset)
+	listBooks, err := h.BUseCase.Fetch(ctx, num, offset)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+		return err
 	}
-	return c.JSON(http.StatusOK, listBooks)
+	return h.respond(c, http.StatusOK, listBooks)
 }
 
 func (h BookHandler) GetById(c echo.Context) error {
-	id, err := strconv.Atoi(c.Param(ID))
+	id, err := bookID(c)
 	if err != nil {
-		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return err
+	}
+	ctx, err := branchContext(c)
+	if err != nil {
+		return err
 	}
 
-	book, err := h.BUseCase.GetById(c.Request().Context(), id)
+	book, err := h.BUseCase.GetById(ctx, id)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+		return err
 	}
-	return c.JSON(http.StatusOK, book)
+	c.Response().Header().Set(HeaderETag, etag(book.Version))
+	return h.respond(c, http.StatusOK, book)
 }
 
 func (h BookHandler) Delete(c echo.Context) error {
-	id, err := strconv.Atoi(c.Param(ID))
+	id, err := bookID(c)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, domain.ErrNotFound.Error())
+		return err
 	}
-
-	err = h.BUseCase.Delete(c.Request().Context(), id)
+	version, err := h.ifMatch(c)
 	if err != nil {
-		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return err
+	}
+
+	if err = h.BUseCase.Delete(c.Request().Context(), id, version); err != nil {
+		return err
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
 func (h BookHandler) Add(c echo.Context) error {
 	var book domain.Book
-	var err error
-	if err = c.Bind(&book); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	if err := c.Bind(&book); err != nil {
+		return err
 	}
-	if ok, err := h.isRequestValid(&book); !ok {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err := h.validator.Struct(&book); err != nil {
+		return err
 	}
 
-	if err = h.BUseCase.Add(c.Request().Context(), &book); err != nil {
-		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	if err := h.BUseCase.Add(c.Request().Context(), &book); err != nil {
+		return err
 	}
-	return c.JSON(http.StatusOK, book)
+	c.Response().Header().Set(HeaderETag, etag(book.Version))
+	return h.respond(c, http.StatusOK, book)
 }
 
 func (h BookHandler) Update(c echo.Context) error {
 	var book domain.Book
 	var err error
-	if err := c.Bind(&book); err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	if err = c.Bind(&book); err != nil {
+		return err
+	}
+	if c.Param(ID) != "" {
+		if book.ID, err = bookID(c); err != nil {
+			return err
+		}
+	} else {
+		// Names the book in the successor link of the legacy route.
+		c.Set(ID, strconv.Itoa(book.ID))
 	}
-	if ok, err := h.isRequestValid(&book); !ok {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if err = h.validator.Struct(&book); err != nil {
+		return err
+	}
+	// The version comes from If-Match only, the one of the body may be stale.
+	if book.Version, err = h.ifMatch(c); err != nil {
+		return err
 	}
 
 	if err = h.BUseCase.Update(c.Request().Context(), &book); err != nil {
-		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+		return err
+	}
+	c.Response().Header().Set(HeaderETag, etag(book.Version))
+	return h.respond(c, http.StatusOK, book)
+}
+
+func (h BookHandler) Patch(c echo.Context) error {
+	id, err := bookID(c)
+	if err != nil {
+		return err
+	}
+	patchType := domain.PatchType(strings.TrimSpace(strings.Split(c.Request().Header.Get(echo.HeaderContentType), ";")[0]))
+	if patchType != domain.MergePatch && patchType != domain.JSONPatch {
+		return ErrUnsupportedPatch
+	}
+	version, err := h.ifMatch(c)
+	if err != nil {
+		return err
+	}
+	patch, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	book, err := h.BUseCase.Patch(c.Request().Context(), id, version, patchType, patch)
+	if err != nil {
+		return err
 	}
-	return c.JSON(http.StatusOK, book)
+	c.Response().Header().Set(HeaderETag, etag(book.Version))
+	return h.respond(c, http.StatusOK, book)
 }
 
-func (h BookHandler) isRequestValid(b *domain.Book) (bool, error) {
-	err := h.validator.Struct(b)
+// respond writes body as JSON, wrapped in an Envelope for the v2 API.
+func (h BookHandler) respond(c echo.Context, code int, body interface{}) error {
+	if h.envelope {
+		return c.JSON(code, Envelope{Data: body})
+	}
+	return c.JSON(code, body)
+}
+
+// branchContext returns the request context limiting the availability of the
+// books to the branch of the branch_id query parameter, if it is set.
+func branchContext(c echo.Context) (context.Context, error) {
+	ctx := c.Request().Context()
+	param := c.QueryParam(BRANCH_ID)
+	if param == "" {
+		return ctx, nil
+	}
+	branchID, err := strconv.Atoi(param)
+	if err != nil || branchID <= 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("branch_id %q is not a branch id", param))
+	}
+	return domain.NewContextWithBranch(ctx, branchID), nil
+}
+
+func bookID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param(ID))
 	if err != nil {
-		return false, err
+		return 0, &domain.InvalidIDError{Param: ID, Value: c.Param(ID)}
 	}
-	return true, nil
+	return id, nil
 }
 
-func getStatusCode(err error) int {
-	if err == nil {
-		return http.StatusOK
+// ifMatch returns the book version the client expects from the If-Match
+// header. Zero means the request is unconditional: either the header is "*"
+// or it is absent and not required. If-Match compares strongly (RFC 7232), a
+// weak ETag never matches.
+func (h BookHandler) ifMatch(c echo.Context) (int, error) {
+	header := strings.TrimSpace(c.Request().Header.Get(HeaderIfMatch))
+	switch header {
+	case "":
+		if h.RequireIfMatch {
+			return 0, ErrPreconditionRequired
+		}
+		return 0, nil
+	case "*":
+		return 0, nil
 	}
 
-	logrus.Error(err)
-	switch err {
-	case domain.ErrInternalServerError:
-		return http.StatusInternalServerError
-	case domain.ErrNotFound:
-		return http.StatusNotFound
-	case domain.ErrConflict:
-		return http.StatusConflict
-	default:
-		return http.StatusInternalServerError
+	if strings.HasPrefix(header, "W/") {
+		return 0, fmt.Errorf("weak ETag %s: %w", header, domain.ErrVersionConflict)
 	}
+	version, err := strconv.Atoi(strings.Trim(header, `"`))
+	if err != nil || version <= 0 {
+		return 0, domain.ErrVersionConflict
+	}
+	return version, nil
+}
+
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
 }
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -10,11 +12,15 @@ import (
 
 	"github.com/bxcodec/faker"
 	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/i18n"
 	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/validation"
 	"github.com/labstack/echo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/go-playground/validator.v9"
 )
 
 func TestFetch(t *testing.T) {
@@ -36,7 +42,7 @@ func TestFetch(t *testing.T) {
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	handler := BookHandler{BUseCase: mockUCase}
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
 	err = handler.FetchBook(c)
 	require.NoError(t, err)
 
@@ -44,6 +50,27 @@ func TestFetch(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 }
 
+func TestFetchByBranch(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+	mockUCase.On("Fetch", mock.MatchedBy(func(ctx context.Context) bool {
+		branchID, ok := domain.BranchFromContext(ctx)
+		return ok && branchID == 2
+	}), 0, 0).Return([]domain.Book{}, nil).Once()
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
+	require.NoError(t, handler.FetchBook(e.NewContext(httptest.NewRequest(echo.GET, "/api/v1/books?branch_id=2", nil), rec)))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+
+	rec = httptest.NewRecorder()
+	err := problem.Middleware()(handler.FetchBook)(e.NewContext(httptest.NewRequest(echo.GET, "/api/v1/books?branch_id=main", nil), rec))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
 func TestUpdate(t *testing.T) {
 	var mockBook domain.Book
 	err := faker.FakeData(&mockBook)
@@ -61,11 +88,192 @@ func TestUpdate(t *testing.T) {
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	handler := BookHandler{BUseCase: mockUCase}
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
+	err = handler.Update(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdateIfMatch(t *testing.T) {
+	var mockBook domain.Book
+	err := faker.FakeData(&mockBook)
+	assert.NoError(t, err)
+	j, err := json.Marshal(mockBook)
+
+	mockUCase := new(mocks.BookUseCase)
+	mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(b *domain.Book) bool { return b.Version == 4 })).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Book).Version++ }).
+		Return(nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.PUT, "/book", strings.NewReader(string(j)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(HeaderIfMatch, `"4"`)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := BookHandler{BUseCase: mockUCase, RequireIfMatch: true, validator: validator.New()}
+	err = handler.Update(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"5"`, rec.Header().Get(HeaderETag))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdateRejectsWeakIfMatch(t *testing.T) {
+	var mockBook domain.Book
+	err := faker.FakeData(&mockBook)
+	assert.NoError(t, err)
+	j, err := json.Marshal(mockBook)
+
+	mockUCase := new(mocks.BookUseCase)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.PUT, "/book", strings.NewReader(string(j)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(HeaderIfMatch, `W/"4"`)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
+	err = problem.Middleware()(handler.Update)(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateWithoutIfMatchIgnoresBodyVersion(t *testing.T) {
+	var mockBook domain.Book
+	err := faker.FakeData(&mockBook)
+	assert.NoError(t, err)
+	mockBook.Version = 3
+	j, err := json.Marshal(mockBook)
+
+	mockUCase := new(mocks.BookUseCase)
+	mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(b *domain.Book) bool { return b.Version == 0 })).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Book).Version = 8 }).
+		Return(nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.PUT, "/book", strings.NewReader(string(j)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
 	err = handler.Update(c)
 	require.NoError(t, err)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"8"`, rec.Header().Get(HeaderETag))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdateIfMatchRequired(t *testing.T) {
+	var mockBook domain.Book
+	err := faker.FakeData(&mockBook)
+	assert.NoError(t, err)
+	j, err := json.Marshal(mockBook)
+
+	mockUCase := new(mocks.BookUseCase)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.PUT, "/book", strings.NewReader(string(j)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := BookHandler{BUseCase: mockUCase, RequireIfMatch: true, validator: validator.New()}
+	err = problem.Middleware()(handler.Update)(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdateVersionConflict(t *testing.T) {
+	var mockBook domain.Book
+	err := faker.FakeData(&mockBook)
+	assert.NoError(t, err)
+	j, err := json.Marshal(mockBook)
+
+	mockUCase := new(mocks.BookUseCase)
+	mockUCase.On("Update", mock.Anything, mock.AnythingOfType("*domain.Book")).
+		Return(domain.ErrVersionConflict)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.PUT, "/book", strings.NewReader(string(j)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(HeaderIfMatch, `"1"`)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
+	err = problem.Middleware()(handler.Update)(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, problem.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestPatch(t *testing.T) {
+	var mockBook domain.Book
+	err := faker.FakeData(&mockBook)
+	assert.NoError(t, err)
+	mockBook.ID = 1
+	patch := `{"title": "Bye"}`
+
+	mockUCase := new(mocks.BookUseCase)
+	mockUCase.On("Patch", mock.Anything, mockBook.ID, 0, domain.MergePatch, []byte(patch)).
+		Return(mockBook, nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.PATCH, "/books/1", strings.NewReader(patch))
+	req.Header.Set(echo.HeaderContentType, string(domain.MergePatch))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("books/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
+	err = handler.Patch(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, etag(mockBook.Version), rec.Header().Get(HeaderETag))
+	mockUCase.AssertExpectations(t)
+}
+
+func TestPatchUnsupportedMediaType(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.PATCH, "/books/1", strings.NewReader(`{"title": "Bye"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("books/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
+	err = problem.Middleware()(handler.Patch)(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
 	mockUCase.AssertExpectations(t)
 }
 
@@ -86,7 +294,7 @@ func TestAdd(t *testing.T) {
 
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	handler := BookHandler{BUseCase: mockUCase}
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
 	err = handler.Add(c)
 	require.NoError(t, err)
 
@@ -97,7 +305,7 @@ func TestAdd(t *testing.T) {
 func TestDelete(t *testing.T) {
 	num := 1
 	mockUCase := new(mocks.BookUseCase)
-	mockUCase.On("Delete", mock.Anything, num).
+	mockUCase.On("Delete", mock.Anything, num, 0).
 		Return(nil)
 
 	e := echo.New()
@@ -110,7 +318,7 @@ func TestDelete(t *testing.T) {
 	c.SetPath("books/:id")
 	c.SetParamNames("id")
 	c.SetParamValues(id)
-	handler := BookHandler{BUseCase: mockUCase}
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
 	err = handler.Delete(c)
 	require.NoError(t, err)
 
@@ -137,10 +345,154 @@ func TestGetById(t *testing.T) {
 	c.SetPath("books/:id")
 	c.SetParamNames("id")
 	c.SetParamValues(id)
-	handler := BookHandler{BUseCase: mockUCase}
+	handler := BookHandler{BUseCase: mockUCase, validator: validator.New()}
 	err = handler.GetById(c)
 	require.NoError(t, err)
 
 	assert.Equal(t, http.StatusOK, rec.Code)
 	mockUCase.AssertExpectations(t)
 }
+
+func TestValidationProblem(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/books", strings.NewReader(`{"content": "World"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := BookHandler{BUseCase: mockUCase, validator: validation.Validator()}
+	err = problem.Middleware()(handler.Add)(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, "/books", p.Instance)
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "title", p.Errors[0].Field)
+	assert.Equal(t, "required", p.Errors[0].Rule)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestValidationProblemLocalized(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.POST, "/books", strings.NewReader(`{"content": "World"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(i18n.HeaderAcceptLanguage, "ru-RU,ru;q=0.9,en;q=0.8")
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := BookHandler{BUseCase: mockUCase, validator: validation.Validator()}
+	err = i18n.Middleware()(problem.Middleware()(handler.Add))(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, i18n.Russian, rec.Header().Get(i18n.HeaderContentLanguage))
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, "запрос содержит ошибки", p.Detail)
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "title является обязательным полем", p.Errors[0].Detail)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestInvalidIDProblem(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+
+	e := echo.New()
+	e.Use(problem.Middleware())
+	NewBookHandler(e, mockUCase, false)
+	req, err := http.NewRequest(echo.GET, "/api/v1/books/abc", nil)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	require.Len(t, p.Errors, 1)
+	assert.Equal(t, "id", p.Errors[0].Field)
+	assert.Equal(t, "numeric", p.Errors[0].Rule)
+	assert.Equal(t, "id must be an integer id", p.Errors[0].Detail)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchInternalError(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+	mockUCase.On("Fetch", mock.Anything, 0, 0).Return(nil, errors.New("connection refused"))
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/books", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := BookHandler{BUseCase: mockUCase}
+	err = problem.Middleware()(handler.FetchBook)(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "connection refused")
+	mockUCase.AssertExpectations(t)
+}
+
+func TestRoutes(t *testing.T) {
+	var mockBook domain.Book
+	err := faker.FakeData(&mockBook)
+	assert.NoError(t, err)
+	mockBook.ID = 1
+
+	mockUCase := new(mocks.BookUseCase)
+	mockUCase.On("GetById", mock.Anything, mockBook.ID).Return(mockBook, nil)
+	mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(b *domain.Book) bool { return b.ID == mockBook.ID })).
+		Return(nil)
+
+	e := echo.New()
+	NewBookHandler(e, mockUCase, false)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		deprecated bool
+		link       string
+		envelope   bool
+	}{
+		{name: "v1", method: echo.GET, target: "/api/v1/books/1"},
+		{name: "v1 update by path", method: echo.PUT, target: "/api/v1/books/1", body: `{"id": 7, "title": "t", "content": "c"}`},
+		{name: "v2", method: echo.GET, target: "/api/v2/books/1", envelope: true},
+		{name: "legacy", method: echo.GET, target: "/books/1", deprecated: true, link: `</api/v1/books/1>; rel="successor-version"`},
+		{name: "legacy update", method: echo.PUT, target: "/book", body: `{"id": 1, "title": "t", "content": "c"}`, deprecated: true,
+			link: `</api/v1/books/1>; rel="successor-version"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			if tt.deprecated {
+				assert.Equal(t, "true", rec.Header().Get(HeaderDeprecation))
+				assert.Equal(t, LegacySunset, rec.Header().Get(HeaderSunset))
+				assert.Equal(t, tt.link, rec.Header().Get(HeaderLink))
+			} else {
+				assert.Empty(t, rec.Header().Get(HeaderDeprecation))
+			}
+			var body map[string]interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			_, hasData := body["data"]
+			assert.Equal(t, tt.envelope, hasData)
+		})
+	}
+}
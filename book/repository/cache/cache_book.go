@@ -0,0 +1,295 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/bxcodec/library/domain"
+	mb "github.com/bxcodec/library/message_broker"
+)
+
+// Stats counts the lookups of a cache and the books it holds.
+type Stats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int    `json:"size"`
+}
+
+// key identifies a cached book. Ids are looked up per tenant, so the tenant is
+// part of the key.
+type key struct {
+	tenant string
+	id     int
+}
+
+type entry struct {
+	key     key
+	book    domain.Book
+	expires time.Time
+}
+
+// Repository is a domain.BookRepository caching the books read by GetById
+// from the repository it wraps. The cache holds the books used last, each for
+// a limited time, and concurrent misses of a book share a single read, given
+// a timeout of its own so that a caller giving up does not fail the others.
+//
+// Add, Update and Delete invalidate the book on this instance, Consume on the
+// others. The replicas may not have the change yet, so an invalidated book is
+// read from the primary for ttl. Fetch is not cached.
+type Repository struct {
+	domain.BookRepository
+
+	size    int
+	ttl     time.Duration
+	timeout time.Duration
+	now     func() time.Time
+	group   singleflight.Group
+
+	mu      sync.Mutex
+	entries map[key]*list.Element
+	recent  *list.List
+	// invalidated holds the books invalidated within ttl. A read started
+	// before the last invalidation of its book is not cached as it may be
+	// stale.
+	invalidated map[key]invalidation
+	// generation numbers the invalidations.
+	generation uint64
+	stats      Stats
+}
+
+// invalidation is the last invalidation of a book.
+type invalidation struct {
+	generation uint64
+	// until is when the book stops being read from the primary.
+	until time.Time
+}
+
+// NewCachedBookRepository returns repo caching up to size books for ttl, the
+// shared reads of a book given timeout.
+func NewCachedBookRepository(repo domain.BookRepository, size int, ttl time.Duration, timeout time.Duration) *Repository {
+	return &Repository{
+		BookRepository: repo,
+		size:           size,
+		ttl:            ttl,
+		timeout:        timeout,
+		now:            time.Now,
+		entries:        make(map[key]*list.Element),
+		recent:         list.New(),
+		invalidated:    make(map[key]invalidation),
+	}
+}
+
+// GetById returns the cached book, reading it on a miss. Reads forced to the
+// primary by ctx skip the cache and refresh it.
+func (r *Repository) GetById(ctx context.Context, id int) (domain.Book, error) {
+	k := keyOf(ctx, id)
+	if domain.PrimaryFromContext(ctx) {
+		return r.read(ctx, k)
+	}
+	if book, ok := r.get(k); ok {
+		return book, nil
+	}
+
+	shared := r.group.DoChan(fmt.Sprintf("%s/%d", k.tenant, k.id), func() (interface{}, error) {
+		ctx, cancel := r.detach(ctx)
+		defer cancel()
+		return r.read(ctx, k)
+	})
+	select {
+	case <-ctx.Done():
+		return domain.Book{}, ctx.Err()
+	case result := <-shared:
+		if result.Err != nil {
+			return domain.Book{}, result.Err
+		}
+		return result.Val.(domain.Book), nil
+	}
+}
+
+// detach returns the context of a read shared by the callers of ctx: it keeps
+// the tenant and the primary flag of ctx, not its deadline, and is given the
+// timeout of the repository.
+func (r *Repository) detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.Background()
+	if tenant, ok := domain.TenantFromContext(ctx); ok {
+		detached = domain.NewContextWithTenant(detached, tenant)
+	}
+	if domain.PrimaryFromContext(ctx) {
+		detached = domain.NewContextWithPrimary(detached)
+	}
+	return context.WithTimeout(detached, r.timeout)
+}
+
+// read reads the book of k from the wrapped repository and caches it, from the
+// primary when the book was invalidated lately.
+func (r *Repository) read(ctx context.Context, k key) (domain.Book, error) {
+	r.mu.Lock()
+	last := r.invalidated[k]
+	if r.now().Before(last.until) {
+		ctx = domain.NewContextWithPrimary(ctx)
+	}
+	r.mu.Unlock()
+
+	book, err := r.BookRepository.GetById(ctx, k.id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	r.put(k, book, last.generation)
+	return book, nil
+}
+
+func (r *Repository) Add(ctx context.Context, book *domain.Book) error {
+	err := r.BookRepository.Add(ctx, book)
+	r.invalidate(keyOf(ctx, book.ID))
+	return err
+}
+
+func (r *Repository) Update(ctx context.Context, book *domain.Book) error {
+	err := r.BookRepository.Update(ctx, book)
+	r.invalidate(keyOf(ctx, book.ID))
+	return err
+}
+
+func (r *Repository) Delete(ctx context.Context, id int, version int) error {
+	err := r.BookRepository.Delete(ctx, id, version)
+	r.invalidate(keyOf(ctx, id))
+	return err
+}
+
+// InTx returns repo, bound to a transaction, invalidating the books it writes
+// once the transaction is committed: invalidating them before would let a
+// concurrent read cache the rows the transaction is about to change. afterCommit
+// registers a function to run on commit. Reads in the transaction are not
+// cached as they may see its uncommitted writes.
+func (r *Repository) InTx(repo domain.BookRepository, afterCommit func(func())) domain.BookRepository {
+	return &txRepository{BookRepository: repo, cache: r, afterCommit: afterCommit}
+}
+
+type txRepository struct {
+	domain.BookRepository
+	cache       *Repository
+	afterCommit func(func())
+}
+
+func (t *txRepository) Add(ctx context.Context, book *domain.Book) error {
+	err := t.BookRepository.Add(ctx, book)
+	t.invalidate(keyOf(ctx, book.ID))
+	return err
+}
+
+func (t *txRepository) Update(ctx context.Context, book *domain.Book) error {
+	err := t.BookRepository.Update(ctx, book)
+	t.invalidate(keyOf(ctx, book.ID))
+	return err
+}
+
+func (t *txRepository) Delete(ctx context.Context, id int, version int) error {
+	err := t.BookRepository.Delete(ctx, id, version)
+	t.invalidate(keyOf(ctx, id))
+	return err
+}
+
+func (t *txRepository) invalidate(k key) {
+	t.afterCommit(func() { t.cache.invalidate(k) })
+}
+
+// Consume invalidates the books changed by the book events, published by other
+// instances, until events is closed or ctx is done.
+func (r *Repository) Consume(ctx context.Context, events <-chan mb.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			switch mb.EventType(event.Subject) {
+			case mb.ADD, mb.UPDATE, mb.DELETE:
+				r.invalidate(key{tenant: event.Tenant, id: event.BookID})
+			}
+		}
+	}
+}
+
+// Stats returns the counters of the cache.
+func (r *Repository) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := r.stats
+	stats.Size = r.recent.Len()
+	return stats
+}
+
+func (r *Repository) get(k key) (domain.Book, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if element, ok := r.entries[k]; ok {
+		e := element.Value.(*entry)
+		if r.now().Before(e.expires) {
+			r.recent.MoveToFront(element)
+			r.stats.Hits++
+			return e.book, true
+		}
+		r.remove(element)
+	}
+	r.stats.Misses++
+	return domain.Book{}, false
+}
+
+// put caches book unless it was invalidated since generation, evicting the
+// book used least recently when the cache is full.
+func (r *Repository) put(k key, book domain.Book, generation uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size <= 0 || generation != r.invalidated[k].generation {
+		return
+	}
+	if element, ok := r.entries[k]; ok {
+		r.remove(element)
+	}
+	r.entries[k] = r.recent.PushFront(&entry{key: k, book: book, expires: r.now().Add(r.ttl)})
+	for r.recent.Len() > r.size {
+		r.remove(r.recent.Back())
+		r.stats.Evictions++
+	}
+}
+
+func (r *Repository) invalidate(k key) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if element, ok := r.entries[k]; ok {
+		r.remove(element)
+	}
+
+	now := r.now()
+	if len(r.invalidated) >= r.size {
+		for invalidated, last := range r.invalidated {
+			if !now.Before(last.until) {
+				delete(r.invalidated, invalidated)
+			}
+		}
+	}
+	r.generation++
+	r.invalidated[k] = invalidation{generation: r.generation, until: now.Add(r.ttl)}
+}
+
+func (r *Repository) remove(element *list.Element) {
+	r.recent.Remove(element)
+	delete(r.entries, element.Value.(*entry).key)
+}
+
+func keyOf(ctx context.Context, id int) key {
+	tenant, _ := domain.TenantFromContext(ctx)
+	return key{tenant: tenant, id: id}
+}
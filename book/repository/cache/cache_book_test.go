@@ -0,0 +1,298 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	mb "github.com/bxcodec/library/message_broker"
+	"github.com/bxcodec/library/mocks"
+)
+
+func TestGetByIdCaches(t *testing.T) {
+	book := domain.Book{ID: 1, Title: "Makan Ayam", Version: 1}
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", mock.Anything, 1).Return(book, nil).Once()
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	for i := 0; i < 3; i++ {
+		got, err := cached.GetById(context.TODO(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, book, got)
+	}
+
+	repo.AssertExpectations(t)
+	assert.Equal(t, Stats{Hits: 2, Misses: 1, Size: 1}, cached.Stats())
+}
+
+func TestGetByIdSeparatesTenants(t *testing.T) {
+	central := domain.NewContextWithTenant(context.TODO(), "central")
+	east := domain.NewContextWithTenant(context.TODO(), "east")
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", ofTenant("central"), 1).Return(domain.Book{ID: 1, Title: "central"}, nil).Once()
+	repo.On("GetById", ofTenant("east"), 1).Return(domain.Book{}, domain.ErrNotFound).Once()
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	got, err := cached.GetById(central, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "central", got.Title)
+	_, err = cached.GetById(east, 1)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	repo.AssertExpectations(t)
+}
+
+// ofTenant matches the contexts of the tenant id.
+func ofTenant(id string) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		tenant, _ := domain.TenantFromContext(ctx)
+		return tenant == id
+	})
+}
+
+func TestGetByIdExpires(t *testing.T) {
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", mock.Anything, 1).Return(domain.Book{ID: 1}, nil).Twice()
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	now := time.Now()
+	cached.now = func() time.Time { return now }
+	_, err := cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+	_, err = cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	assert.Equal(t, uint64(2), cached.Stats().Misses)
+}
+
+func TestGetByIdEvictsLeastRecentlyUsed(t *testing.T) {
+	repo := new(mocks.BookRepository)
+	for id := 1; id <= 3; id++ {
+		repo.On("GetById", mock.Anything, id).Return(domain.Book{ID: id}, nil)
+	}
+
+	cached := NewCachedBookRepository(repo, 2, time.Minute, time.Second)
+	for _, id := range []int{1, 2, 1, 3, 1, 2} {
+		_, err := cached.GetById(context.TODO(), id)
+		require.NoError(t, err)
+	}
+
+	repo.AssertNumberOfCalls(t, "GetById", 4)
+	assert.Equal(t, Stats{Hits: 2, Misses: 4, Evictions: 2, Size: 2}, cached.Stats())
+}
+
+func TestGetByIdCollapsesConcurrentMisses(t *testing.T) {
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", mock.Anything, 1).Return(domain.Book{ID: 1}, nil).
+		WaitUntil(time.After(50 * time.Millisecond)).Once()
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			book, err := cached.GetById(context.TODO(), 1)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, book.ID)
+		}()
+	}
+	wg.Wait()
+
+	repo.AssertExpectations(t)
+}
+
+func TestChangesInvalidate(t *testing.T) {
+	book := domain.Book{ID: 1, Version: 1}
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", mock.Anything, 1).Return(book, nil)
+	repo.On("Update", mock.Anything, &book).Return(nil)
+	repo.On("Delete", mock.Anything, 1, 0).Return(nil)
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	_, err := cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	require.NoError(t, cached.Update(context.TODO(), &book))
+	_, err = cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	require.NoError(t, cached.Delete(context.TODO(), 1, 0))
+	_, err = cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+
+	repo.AssertNumberOfCalls(t, "GetById", 3)
+}
+
+func TestInvalidationDuringReadIsNotCached(t *testing.T) {
+	book := domain.Book{ID: 1, Version: 1}
+	reading, release := make(chan struct{}), make(chan struct{})
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", mock.Anything, 1).Return(book, nil).Run(func(mock.Arguments) {
+		close(reading)
+		<-release
+	}).Once()
+	repo.On("Update", mock.Anything, &book).Return(nil)
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := cached.GetById(context.TODO(), 1)
+		assert.NoError(t, err)
+	}()
+	<-reading
+	require.NoError(t, cached.Update(context.TODO(), &book))
+	close(release)
+	<-done
+
+	assert.Zero(t, cached.Stats().Size)
+}
+
+func TestConsumeInvalidates(t *testing.T) {
+	ctx := domain.NewContextWithTenant(context.TODO(), "central")
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", mock.Anything, 1).Return(domain.Book{ID: 1}, nil)
+	repo.On("GetById", mock.Anything, 2).Return(domain.Book{ID: 2}, nil)
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	for _, id := range []int{1, 2} {
+		_, err := cached.GetById(ctx, id)
+		require.NoError(t, err)
+	}
+
+	events := make(chan mb.Event, 3)
+	events <- mb.Event{Subject: string(mb.GetById), Tenant: "central", BookID: 1}
+	events <- mb.Event{Subject: string(mb.UPDATE), Tenant: "east", BookID: 1}
+	events <- mb.Event{Subject: string(mb.UPDATE), Tenant: "central", BookID: 2}
+	close(events)
+	cached.Consume(context.TODO(), events)
+
+	assert.Equal(t, 1, cached.Stats().Size)
+	_, err := cached.GetById(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), cached.Stats().Hits)
+}
+
+func TestPrimaryReadsRefresh(t *testing.T) {
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", mock.Anything, 1).Return(domain.Book{ID: 1, Version: 1}, nil).Once()
+	repo.On("GetById", mock.Anything, 1).Return(domain.Book{ID: 1, Version: 2}, nil).Once()
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	_, err := cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	book, err := cached.GetById(domain.NewContextWithPrimary(context.TODO()), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, book.Version)
+	book, err = cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, book.Version)
+
+	repo.AssertExpectations(t)
+}
+
+func TestChangesInTxInvalidateOnCommit(t *testing.T) {
+	book := domain.Book{ID: 1, Version: 1}
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", mock.Anything, 1).Return(book, nil)
+	txRepo := new(mocks.BookRepository)
+	txRepo.On("Update", mock.Anything, &book).Return(nil)
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	_, err := cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+
+	var committed []func()
+	inTx := cached.InTx(txRepo, func(fn func()) { committed = append(committed, fn) })
+	require.NoError(t, inTx.Update(context.TODO(), &book))
+	_, err = cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "GetById", 1)
+
+	require.Len(t, committed, 1)
+	committed[0]()
+	_, err = cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "GetById", 2)
+	txRepo.AssertExpectations(t)
+}
+
+func TestInvalidatedBooksAreReadFromThePrimary(t *testing.T) {
+	book := domain.Book{ID: 1, Version: 1}
+	updated := domain.Book{ID: 1, Version: 2}
+	fromPrimary := mock.MatchedBy(func(ctx context.Context) bool { return domain.PrimaryFromContext(ctx) })
+	fromReplica := mock.MatchedBy(func(ctx context.Context) bool { return !domain.PrimaryFromContext(ctx) })
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", fromReplica, 1).Return(book, nil).Once()
+	repo.On("Update", mock.Anything, &updated).Return(nil)
+	repo.On("GetById", fromPrimary, 1).Return(updated, nil).Once()
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	_, err := cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	require.NoError(t, cached.Update(context.TODO(), &updated))
+	for i := 0; i < 2; i++ {
+		got, err := cached.GetById(context.TODO(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, updated, got)
+	}
+
+	repo.AssertExpectations(t)
+}
+
+func TestGetByIdSharesReadsBeyondCallersGivingUp(t *testing.T) {
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", ofTenant("central"), 1).Return(domain.Book{ID: 1}, nil).
+		WaitUntil(time.After(50 * time.Millisecond)).Once()
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	central := domain.NewContextWithTenant(context.TODO(), "central")
+	impatient, cancel := context.WithTimeout(central, 10*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := cached.GetById(impatient, 1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}()
+	time.Sleep(time.Millisecond)
+	book, err := cached.GetById(central, 1)
+	wg.Wait()
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, book.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestInvalidationKeepsOtherReadsCacheable(t *testing.T) {
+	release := make(chan time.Time)
+	repo := new(mocks.BookRepository)
+	repo.On("GetById", mock.Anything, 1).Return(domain.Book{ID: 1}, nil).WaitUntil(release).Once()
+	repo.On("Delete", mock.Anything, 2, 0).Return(nil)
+
+	cached := NewCachedBookRepository(repo, 10, time.Minute, time.Second)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := cached.GetById(context.TODO(), 1)
+		assert.NoError(t, err)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, cached.Delete(context.TODO(), 2, 0))
+	close(release)
+	<-done
+
+	_, err := cached.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	assert.Equal(t, uint64(1), cached.Stats().Hits)
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/library/domain"
+)
+
+type memoryBookRepository struct {
+	mu     sync.RWMutex
+	books  map[int]domain.Book
+	lastID int
+}
+
+// NewMemoryBookRepository returns an empty repository keeping the books in
+// memory. It is safe for concurrent use.
+func NewMemoryBookRepository() domain.BookRepository {
+	return &memoryBookRepository{books: make(map[int]domain.Book)}
+}
+
+// Fetch returns the books ordered by id. A num of 0 returns all of them, an
+// offset below 0 starts at the first.
+func (m *memoryBookRepository) Fetch(ctx context.Context, num int, offset int) ([]domain.Book, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]domain.Book, 0, len(m.books))
+	for _, book := range m.books {
+		result = append(result, book)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(result) {
+		offset = len(result)
+	}
+	result = result[offset:]
+	if num > 0 && num < len(result) {
+		result = result[:num]
+	}
+	return result, nil
+}
+
+// Add stores the book with a new id and the creation and update times set to
+// now.
+func (m *memoryBookRepository) Add(ctx context.Context, book *domain.Book) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+	now := time.Now().UTC()
+	book.ID, book.Version, book.CreatedAt, book.UpdatedAt = m.lastID, 1, now, now
+	m.books[book.ID] = stored(*book)
+	return nil
+}
+
+// Delete removes the book. A non-zero version makes the delete conditional on
+// the stored version, so a stale client gets domain.ErrVersionConflict.
+func (m *memoryBookRepository) Delete(ctx context.Context, id int, version int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.check(id, version); err != nil {
+		return err
+	}
+	delete(m.books, id)
+	return nil
+}
+
+// Update stores the book and bumps its version. A non-zero book.Version is
+// checked against the stored one, on success book.Version holds the new value.
+// The creation time is kept and the update time is set to now.
+func (m *memoryBookRepository) Update(ctx context.Context, book *domain.Book) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.check(book.ID, book.Version); err != nil {
+		return err
+	}
+	existing := m.books[book.ID]
+	book.Version, book.CreatedAt, book.UpdatedAt = existing.Version+1, existing.CreatedAt, time.Now().UTC()
+	m.books[book.ID] = stored(*book)
+	return nil
+}
+
+func (m *memoryBookRepository) GetById(ctx context.Context, id int) (domain.Book, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	book, ok := m.books[id]
+	if !ok {
+		return domain.Book{}, fmt.Errorf("book %d: %w", id, domain.ErrNotFound)
+	}
+	return book, nil
+}
+
+// check returns the error of changing the book id at version, 0 matching any.
+func (m *memoryBookRepository) check(id int, version int) error {
+	existing, ok := m.books[id]
+	switch {
+	case !ok:
+		return fmt.Errorf("book %d: %w", id, domain.ErrNotFound)
+	case version != 0 && existing.Version != version:
+		return fmt.Errorf("book %d version %d: %w", id, version, domain.ErrVersionConflict)
+	}
+	return nil
+}
+
+// stored returns the book as the repository keeps it, with the id of its author
+// only and no availability.
+func stored(book domain.Book) domain.Book {
+	book.Author = domain.Author{ID: book.Author.ID}
+	book.Availability = nil
+	return book
+}
@@ -0,0 +1,14 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.BookRepository(t, func(t *testing.T, authors ...string) domain.BookRepository {
+		return NewMemoryBookRepository()
+	})
+}
@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/replica"
+	"github.com/bxcodec/library/internal/repotest"
+)
+
+func open(t *testing.T, authors ...string) domain.BookRepository {
+	repo := NewPostgresBookRepository(replica.New(repotest.Postgres(t, authors...), nil))
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// TestContract runs against the database of repotest.EnvPostgres and is
+// skipped without one.
+func TestContract(t *testing.T) {
+	repotest.BookRepository(t, open)
+	repotest.BookAuthors(t, open)
+}
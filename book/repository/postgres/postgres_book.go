@@ -3,22 +3,86 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/query"
+	"github.com/bxcodec/library/internal/replica"
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
-type postgresBookRepository struct {
-	Conn *sql.DB
+const foreignKeyViolation = "23503"
+
+// sortable are the columns books may be sorted by.
+var sortable = query.Columns{
+	"id":         "b.id",
+	"title":      "b.title",
+	"created_at": "b.created_at",
+	"updated_at": "b.updated_at",
+}
+
+// PostgresBookRepository is the domain.BookRepository of a Postgres database.
+type PostgresBookRepository struct {
+	Conn  *replica.Cluster
+	tx    *sql.Tx
+	stmts *query.Statements
+}
+
+// NewPostgresBookRepository returns the books of Conn, read from its replicas
+// and written to its primary.
+func NewPostgresBookRepository(Conn *replica.Cluster) *PostgresBookRepository {
+	return &PostgresBookRepository{Conn: Conn, stmts: query.NewStatements()}
+}
+
+// NewPostgresBookRepositoryTx returns the books of tx, read and written in it
+// by a unit of work.
+func NewPostgresBookRepositoryTx(tx *sql.Tx) domain.BookRepository {
+	return &PostgresBookRepository{tx: tx}
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// writer returns where books are written: the transaction of the repository,
+// if any, or the primary.
+func (p *PostgresBookRepository) writer() execer {
+	if p.tx != nil {
+		return p.tx
+	}
+	return p.Conn.Primary()
+}
+
+// read runs the statement text on the transaction of the repository, if any,
+// or prepared on a reader of ctx.
+func (p *PostgresBookRepository) read(c context.Context, text string, args ...interface{}) (*sql.Rows, error) {
+	if p.tx != nil {
+		return p.tx.QueryContext(c, text, args...)
+	}
+	stmt, err := p.stmts.Prepare(c, p.Conn.Reader(c), text)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(c, args...)
 }
 
-func NewPostgresBookRepository(Conn *sql.DB) domain.BookRepository {
-	return &postgresBookRepository{Conn}
+// selectBooks starts the statement selecting books. Only the id of their
+// author is loaded, the use case hydrates the authors of a page at once.
+func selectBooks() *query.Builder {
+	return query.Select("b.id", "b.title", "b.content", "b.author_id", "b.version", "b.created_at", "b.updated_at").
+		From("book as b").
+		Sortable(sortable)
 }
 
-func (p *postgresBookRepository) fetch(c context.Context, query string) ([]domain.Book, error) {
-	rows, err := p.Conn.QueryContext(c, query)
+func (p *PostgresBookRepository) fetch(c context.Context, b *query.Builder) ([]domain.Book, error) {
+	text, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := p.read(c, text, args...)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -39,11 +103,9 @@ func (p *postgresBookRepository) fetch(c context.Context, query string) ([]domai
 			&book.Title,
 			&book.Content,
 			&book.Author.ID,
-			&book.Author.Name,
-			&book.Author.CreatedAt,
-			&book.Author.UpdatedAt,
-			&book.UpdatedAt,
+			&book.Version,
 			&book.CreatedAt,
+			&book.UpdatedAt,
 		)
 
 		if err != nil {
@@ -52,48 +114,31 @@ func (p *postgresBookRepository) fetch(c context.Context, query string) ([]domai
 		}
 		result = append(result, book)
 	}
-	return result, nil
+	return result, rows.Err()
 }
 
-func (p *postgresBookRepository) Fetch(ctx context.Context, num int, offset int) ([]domain.Book, error) {
-	query := fmt.Sprintf("SELECT b.id, b.title, b.content, a.id, a.name, a.created_at, a.updated_at, b.created_at, b.updated_at " +
-		"FROM book as b " +
-		"INNER JOIN author a on b.author_id = a.id " +
-		"ORDER BY b.id")
-	if num != 0 {
-		query = fmt.Sprintf(query+" LIMIT %d", num)
-	}
-	if offset != 0 {
-		query = fmt.Sprintf(query+" OFFSET %d", offset)
-	}
-
-	res, err := p.fetch(ctx, query)
+func (p *PostgresBookRepository) Fetch(ctx context.Context, num int, offset int) ([]domain.Book, error) {
+	res, err := p.fetch(ctx, selectBooks().OrderBy("id", query.Asc).Limit(num).Offset(offset))
 	if err != nil {
 		return nil, err
 	}
 	return res, err
 }
 
-func (p *postgresBookRepository) Add(ctx context.Context, book *domain.Book) error {
-	res, err := p.Conn.ExecContext(ctx, `INSERT INTO book (title, content, author_id, updated_at, created_at) VALUES ($1, $2, $3, $4, $5)`,
-		book.Title, book.Content, book.Author.ID, book.UpdatedAt, book.CreatedAt)
-	if err != nil {
-		return err
-	}
-	rowsAffected, err := res.RowsAffected()
+// Add stores the book with the creation and update times set by the database.
+func (p *PostgresBookRepository) Add(ctx context.Context, book *domain.Book) error {
+	err := p.writer().QueryRowContext(ctx, `INSERT INTO book (title, content, author_id) VALUES ($1, $2, $3) RETURNING id, version, created_at, updated_at`,
+		book.Title, book.Content, book.Author.ID).Scan(&book.ID, &book.Version, &book.CreatedAt, &book.UpdatedAt)
 	if err != nil {
-		return err
-	}
-	if rowsAffected != 1 {
-		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", rowsAffected)
-		return err
+		return unknownAuthor(err, book)
 	}
-
-	return err
+	return nil
 }
 
-func (p *postgresBookRepository) Delete(ctx context.Context, id int) error {
-	res, err := p.Conn.ExecContext(ctx, `DELETE FROM book WHERE id = $1`, id)
+// Delete removes the book. A non-zero version makes the delete conditional on
+// the stored version, so a stale client gets domain.ErrVersionConflict.
+func (p *PostgresBookRepository) Delete(ctx context.Context, id int, version int) error {
+	res, err := p.writer().ExecContext(ctx, `DELETE FROM book WHERE id = $1 AND ($2 = 0 OR version = $2)`, id, version)
 	if err != nil {
 		return err
 	}
@@ -101,6 +146,9 @@ func (p *postgresBookRepository) Delete(ctx context.Context, id int) error {
 	if err != nil {
 		return err
 	}
+	if rowsAffected == 0 {
+		return notAffected(id, version)
+	}
 	if rowsAffected != 1 {
 		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", rowsAffected)
 		return err
@@ -108,34 +156,51 @@ func (p *postgresBookRepository) Delete(ctx context.Context, id int) error {
 	return err
 }
 
-func (p *postgresBookRepository) Update(ctx context.Context, book *domain.Book) error {
-	res, err := p.Conn.ExecContext(ctx, `UPDATE  book SET title = $1, content = $2, author_id = $3, updated_at = $4, created_at = $5 WHERE id = $6`,
-		book.Title, book.Content, book.Author.ID, book.UpdatedAt, book.CreatedAt, book.ID)
-	if err != nil {
-		return err
-	}
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rowsAffected != 1 {
-		err = fmt.Errorf("Weird  Behavior. Total Affected: %d", rowsAffected)
-		return err
-	}
-	return err
+// Update stores the book and bumps its version. A non-zero book.Version is
+// checked against the stored one, on success book.Version holds the new value.
+// The creation time is kept and the update time is set by the database.
+func (p *PostgresBookRepository) Update(ctx context.Context, book *domain.Book) error {
+	err := p.writer().QueryRowContext(ctx, `UPDATE book SET title = $1, content = $2, author_id = $3, updated_at = now(), version = version + 1 `+
+		`WHERE id = $4 AND ($5 = 0 OR version = $5) RETURNING version, created_at, updated_at`,
+		book.Title, book.Content, book.Author.ID, book.ID, book.Version).Scan(&book.Version, &book.CreatedAt, &book.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return notAffected(book.ID, book.Version)
+	}
+	return unknownAuthor(err, book)
 }
 
-func (p *postgresBookRepository) GetById(ctx context.Context, id int) (domain.Book, error) {
-	query := fmt.Sprintf("SELECT b.id, b.title, b.content, a.id, a.name, a.created_at, a.updated_at, b.created_at, b.updated_at "+
-		"FROM book as b "+
-		"INNER JOIN author a on b.author_id = a.id "+
-		"WHERE b.id = %d", id)
-	list, err := p.fetch(ctx, query)
+func (p *PostgresBookRepository) GetById(ctx context.Context, id int) (domain.Book, error) {
+	list, err := p.fetch(ctx, selectBooks().Where("b.id = ?", id))
 	if err != nil {
 		return domain.Book{}, err
 	}
 	if len(list) == 0 {
-		return domain.Book{}, domain.ErrNotFound
+		return domain.Book{}, fmt.Errorf("book %d: %w", id, domain.ErrNotFound)
 	}
 	return list[0], nil
 }
+
+// Close releases the statements prepared by the repository.
+func (p *PostgresBookRepository) Close() error {
+	if p.stmts == nil {
+		return nil
+	}
+	return p.stmts.Close()
+}
+
+// unknownAuthor reports a book referencing a missing author as
+// domain.ErrNotFound.
+func unknownAuthor(err error, book *domain.Book) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return fmt.Errorf("author %d: %w", book.Author.ID, domain.ErrNotFound)
+	}
+	return err
+}
+
+func notAffected(id int, version int) error {
+	if version != 0 {
+		return fmt.Errorf("book %d version %d: %w", id, version, domain.ErrVersionConflict)
+	}
+	return fmt.Errorf("book %d: %w", id, domain.ErrNotFound)
+}
@@ -2,15 +2,20 @@ package postgres
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/replica"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/DATA-DOG/go-sqlmock.v1"
 )
 
+var bookColumns = []string{"b.id", "b.title", "b.content", "b.author_id", "b.version", "b.created_at", "b.updated_at"}
+
 func TestFetch(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -19,27 +24,47 @@ func TestFetch(t *testing.T) {
 	books := make([]domain.Book, 2)
 	books[0] = domain.Book{
 		ID: 1, Title: "title 1", Content: "content 1",
-		Author: domain.Author{ID: 1}, UpdatedAt: time.Now().String(), CreatedAt: time.Now().String(),
+		Author: domain.Author{ID: 1}, UpdatedAt: time.Now(), CreatedAt: time.Now(),
 	}
 	books[1] = domain.Book{
 		ID: 2, Title: "title 2", Content: "content 2",
-		Author: domain.Author{ID: 1}, UpdatedAt: time.Now().String(), CreatedAt: time.Now().String(),
+		Author: domain.Author{ID: 1}, Version: 1, UpdatedAt: time.Now(), CreatedAt: time.Now(),
 	}
-	rows := sqlmock.NewRows([]string{"b.id", "b.title", "b.content", "a.id", "a.name", "a.created_at", "a.updated_at", "b.created_at", "b.updated_at"}).
-		AddRow(books[0].ID, books[0].Title, books[0].Content, books[0].Author.ID, books[0].Author.Name, books[0].Author.CreatedAt, books[0].Author.UpdatedAt, books[0].UpdatedAt, books[0].CreatedAt).
-		AddRow(books[1].ID, books[1].Title, books[1].Content, books[1].Author.ID, books[1].Author.Name, books[1].Author.CreatedAt, books[1].Author.UpdatedAt, books[1].UpdatedAt, books[1].CreatedAt)
+	rows := sqlmock.NewRows(bookColumns).
+		AddRow(books[0].ID, books[0].Title, books[0].Content, books[0].Author.ID, books[0].Version, books[0].CreatedAt, books[0].UpdatedAt).
+		AddRow(books[1].ID, books[1].Title, books[1].Content, books[1].Author.ID, books[1].Version, books[1].CreatedAt, books[1].UpdatedAt)
 
 	var num = 2
 	var offset = 1
-	query := fmt.Sprintf("SELECT b.id, b.title, b.content, a.id, a.name, a.created_at, a.updated_at, b.created_at, b.updated_at "+
-		"FROM book as b "+
-		"INNER JOIN author a on b.author_id = a.id "+
-		"ORDER BY b.id LIMIT %d OFFSET %d", num, offset)
-	mock.ExpectQuery(query).WillReturnRows(rows)
+	query := "SELECT b.id, b.title, b.content, b.author_id, b.version, b.created_at, b.updated_at " +
+		"FROM book as b " +
+		"ORDER BY b.id ASC LIMIT $1 OFFSET $2"
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).ExpectQuery().WithArgs(num, offset).WillReturnRows(rows)
 
-	bookRepository := NewPostgresBookRepository(db)
+	bookRepository := NewPostgresBookRepository(replica.New(db, nil))
 	result, err := bookRepository.Fetch(context.TODO(), num, offset)
-	assert.Equal(t, num, len(result))
+	assert.NoError(t, err)
+	assert.Equal(t, books, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchWithoutLimit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error %s occured while testing", err)
+	}
+	rows := sqlmock.NewRows(bookColumns)
+
+	query := "SELECT b.id, b.title, b.content, b.author_id, b.version, b.created_at, b.updated_at " +
+		"FROM book as b " +
+		"ORDER BY b.id ASC"
+	mock.ExpectPrepare(regexp.QuoteMeta(query) + "$").ExpectQuery().WithArgs().WillReturnRows(rows)
+
+	bookRepository := NewPostgresBookRepository(replica.New(db, nil))
+	result, err := bookRepository.Fetch(context.TODO(), 0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestAdd(t *testing.T) {
@@ -51,24 +76,28 @@ func TestAdd(t *testing.T) {
 	author := domain.Author{
 		ID:        1,
 		Name:      "Author X",
-		CreatedAt: time.Now().String(),
-		UpdatedAt: time.Now().String(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
 	book := &domain.Book{
 		Title:     "KISS",
 		Content:   "That Girl",
 		Author:    author,
-		UpdatedAt: time.Now().String(),
-		CreatedAt: time.Now().String(),
+		CreatedAt: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
+	now := time.Now()
 
-	mock.ExpectExec("INSERT INTO book (title, content, author_id, updated_at, created_at)*").
-		WithArgs(book.Title, book.Content, author.ID, book.UpdatedAt, book.CreatedAt).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO book (title, content, author_id) VALUES ($1, $2, $3) RETURNING id, version, created_at, updated_at")).
+		WithArgs(book.Title, book.Content, author.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}).AddRow(7, 1, now, now))
 
-	bookRepository := NewPostgresBookRepository(db)
+	bookRepository := NewPostgresBookRepository(replica.New(db, nil))
 	err = bookRepository.Add(context.TODO(), book)
 	assert.NoError(t, err)
+	assert.Equal(t, 7, book.ID)
+	assert.Equal(t, 1, book.Version)
+	assert.Equal(t, now, book.CreatedAt)
+	assert.Equal(t, now, book.UpdatedAt)
 }
 
 func TestDelete(t *testing.T) {
@@ -78,13 +107,26 @@ func TestDelete(t *testing.T) {
 	}
 	var num = 1
 	mock.ExpectExec("DELETE FROM book WHERE id = ").
-		WithArgs(1).
+		WithArgs(1, 0).
 		WillReturnResult(sqlmock.NewResult(int64(num), 1))
-	bookRepository := NewPostgresBookRepository(db)
-	err = bookRepository.Delete(context.TODO(), int(num))
+	bookRepository := NewPostgresBookRepository(replica.New(db, nil))
+	err = bookRepository.Delete(context.TODO(), int(num), 0)
 	assert.NoError(t, err)
 }
 
+func TestDeleteVersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error %s occured while testing", err)
+	}
+	mock.ExpectExec("DELETE FROM book WHERE id = ").
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	bookRepository := NewPostgresBookRepository(replica.New(db, nil))
+	err = bookRepository.Delete(context.TODO(), 1, 2)
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+}
+
 func TestUpdate(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -94,23 +136,49 @@ func TestUpdate(t *testing.T) {
 	author := domain.Author{
 		ID:        1,
 		Name:      "Author X",
-		CreatedAt: time.Now().String(),
-		UpdatedAt: time.Now().String(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
 	book := &domain.Book{
 		Title:     "KISS",
 		Content:   "That Girl",
 		Author:    author,
-		UpdatedAt: time.Now().String(),
-		CreatedAt: time.Now().String(),
+		UpdatedAt: time.Now(),
+		CreatedAt: time.Now(),
 	}
 
-	mock.ExpectExec(`UPDATE book SET *`).
-		WithArgs(book.Title, book.Content, book.Author.ID, book.UpdatedAt, book.CreatedAt, book.ID).
-		WillReturnResult(sqlmock.NewResult(int64(num), 1))
-	bookRepository := NewPostgresBookRepository(db)
+	created := time.Date(2017, 5, 18, 13, 50, 19, 0, time.UTC)
+	updated := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE book SET title = $1, content = $2, author_id = $3, updated_at = now(), version = version + 1 `)).
+		WithArgs(book.Title, book.Content, book.Author.ID, book.ID, book.Version).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "created_at", "updated_at"}).AddRow(num+1, created, updated))
+	bookRepository := NewPostgresBookRepository(replica.New(db, nil))
 	err = bookRepository.Update(context.TODO(), book)
 	assert.NoError(t, err)
+	assert.Equal(t, num+1, book.Version)
+	assert.Equal(t, created, book.CreatedAt)
+	assert.Equal(t, updated, book.UpdatedAt)
+}
+
+func TestUpdateVersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error %s occured while testing", err)
+	}
+	book := &domain.Book{
+		ID:      1,
+		Title:   "KISS",
+		Content: "That Girl",
+		Version: 3,
+	}
+
+	mock.ExpectQuery(`UPDATE book SET *`).
+		WithArgs(book.Title, book.Content, book.Author.ID, book.ID, book.Version).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "created_at", "updated_at"}))
+	bookRepository := NewPostgresBookRepository(replica.New(db, nil))
+	err = bookRepository.Update(context.TODO(), book)
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
 }
 
 func TestGetById(t *testing.T) {
@@ -120,19 +188,85 @@ func TestGetById(t *testing.T) {
 	}
 	book := domain.Book{
 		ID: 1, Title: "title 1", Content: "content 1",
-		Author: domain.Author{ID: 1}, UpdatedAt: time.Now().String(), CreatedAt: time.Now().String(),
+		Author: domain.Author{ID: 1}, Version: 1, UpdatedAt: time.Now(), CreatedAt: time.Now(),
 	}
-	rows := sqlmock.NewRows([]string{"b.id", "b.title", "b.content", "a.id", "a.name", "a.created_at", "a.updated_at", "b.created_at", "b.updated_at"}).
-		AddRow(book.ID, book.Title, book.Content, book.Author.ID, book.Author.Name, book.Author.CreatedAt, book.Author.UpdatedAt, book.UpdatedAt, book.CreatedAt)
+	rows := sqlmock.NewRows(bookColumns).
+		AddRow(book.ID, book.Title, book.Content, book.Author.ID, book.Version, book.CreatedAt, book.UpdatedAt)
 
 	var id = 1
-	query := fmt.Sprintf("SELECT b.id, b.title, b.content, a.id, a.name, a.created_at, a.updated_at, b.created_at, b.updated_at "+
-		"FROM book as b "+
-		"INNER JOIN author a on b.author_id = a.id "+
-		"WHERE b.id = %d", id)
-	mock.ExpectQuery(query).WillReturnRows(rows)
+	query := "SELECT b.id, b.title, b.content, b.author_id, b.version, b.created_at, b.updated_at " +
+		"FROM book as b " +
+		"WHERE b.id = $1"
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).ExpectQuery().WithArgs(id).WillReturnRows(rows)
 
-	bookRepository := NewPostgresBookRepository(db)
+	bookRepository := NewPostgresBookRepository(replica.New(db, nil))
 	bookActual, err := bookRepository.GetById(context.TODO(), id)
+	assert.NoError(t, err)
 	assert.Equal(t, book, bookActual)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchReusesStatement(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("An error %s occured while testing", err)
+	}
+	query := "SELECT b.id, b.title, b.content, b.author_id, b.version, b.created_at, b.updated_at " +
+		"FROM book as b " +
+		"ORDER BY b.id ASC LIMIT $1 OFFSET $2"
+	prepared := mock.ExpectPrepare(regexp.QuoteMeta(query))
+	prepared.ExpectQuery().WithArgs(10, 10).WillReturnRows(sqlmock.NewRows(bookColumns))
+	prepared.ExpectQuery().WithArgs(10, 20).WillReturnRows(sqlmock.NewRows(bookColumns))
+	prepared.WillBeClosed()
+
+	bookRepository := NewPostgresBookRepository(replica.New(db, nil))
+	_, err = bookRepository.Fetch(context.TODO(), 10, 10)
+	assert.NoError(t, err)
+	_, err = bookRepository.Fetch(context.TODO(), 10, 20)
+	assert.NoError(t, err)
+	assert.NoError(t, bookRepository.Close())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReadsGoToReplicas(t *testing.T) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	replicaDB, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	getByID := regexp.QuoteMeta("SELECT b.id, b.title, b.content, b.author_id, b.version, b.created_at, b.updated_at FROM book as b WHERE b.id = $1")
+	replicaMock.ExpectPrepare(getByID).ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows(bookColumns).AddRow(1, "title", "content", 1, 1, time.Now(), time.Now()))
+	primaryMock.ExpectPrepare(getByID).ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows(bookColumns).AddRow(1, "title", "content", 1, 2, time.Now(), time.Now()))
+	primaryMock.ExpectExec("DELETE FROM book WHERE id = ").WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	bookRepository := NewPostgresBookRepository(replica.New(primary, map[string]*sql.DB{"replica": replicaDB}))
+	book, err := bookRepository.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, book.Version)
+	book, err = bookRepository.GetById(domain.NewContextWithPrimary(context.TODO()), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, book.Version)
+	require.NoError(t, bookRepository.Delete(context.TODO(), 1, 2))
+
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestTxRunsInTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM book as b WHERE b.id = $1")).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(bookColumns).AddRow(1, "title", "content", 1, 1, time.Now(), time.Now()))
+	mock.ExpectExec("DELETE FROM book WHERE id = ").WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	bookRepository := NewPostgresBookRepositoryTx(tx)
+	_, err = bookRepository.GetById(context.TODO(), 1)
+	require.NoError(t, err)
+	require.NoError(t, bookRepository.Delete(context.TODO(), 1, 1))
+	require.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
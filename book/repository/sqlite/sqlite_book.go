@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/query"
+	"github.com/sirupsen/logrus"
+	driver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sortable are the columns books may be sorted by.
+var sortable = query.Columns{
+	"id":         "b.id",
+	"title":      "b.title",
+	"created_at": "b.created_at",
+	"updated_at": "b.updated_at",
+}
+
+// now is the current time as SQLite stores it, to the millisecond.
+const now = `strftime('%Y-%m-%d %H:%M:%f', 'now')`
+
+// SQLiteBookRepository is the domain.BookRepository of a SQLite database.
+type SQLiteBookRepository struct {
+	Conn  *sql.DB
+	stmts *query.Statements
+}
+
+// NewSQLiteBookRepository returns the books stored in the SQLite database of
+// Conn, migrated with migrations.SQLite.
+func NewSQLiteBookRepository(Conn *sql.DB) *SQLiteBookRepository {
+	return &SQLiteBookRepository{Conn: Conn, stmts: query.NewStatements()}
+}
+
+// selectBooks starts the statement selecting books. Only the id of their
+// author is loaded, the use case hydrates the authors of a page at once.
+func selectBooks() *query.Builder {
+	return query.Select("b.id", "b.title", "b.content", "b.author_id", "b.version", "b.created_at", "b.updated_at").
+		From("book as b").
+		Sortable(sortable)
+}
+
+func (s *SQLiteBookRepository) fetch(c context.Context, b *query.Builder) ([]domain.Book, error) {
+	text, args, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := s.stmts.Prepare(c, s.Conn, text)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(c, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make([]domain.Book, 0)
+	for rows.Next() {
+		book := domain.Book{}
+		err = rows.Scan(
+			&book.ID,
+			&book.Title,
+			&book.Content,
+			&book.Author.ID,
+			&book.Version,
+			&book.CreatedAt,
+			&book.UpdatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, book)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteBookRepository) Fetch(ctx context.Context, num int, offset int) ([]domain.Book, error) {
+	return s.fetch(ctx, selectBooks().OrderBy("id", query.Asc).Limit(num).Offset(offset))
+}
+
+// Add stores the book with the creation and update times set by the database.
+func (s *SQLiteBookRepository) Add(ctx context.Context, book *domain.Book) error {
+	err := s.Conn.QueryRowContext(ctx, `INSERT INTO book (title, content, author_id) VALUES ($1, $2, $3) RETURNING id, version, created_at, updated_at`,
+		book.Title, book.Content, book.Author.ID).Scan(&book.ID, &book.Version, &book.CreatedAt, &book.UpdatedAt)
+	return unknownAuthor(err, book)
+}
+
+// Delete removes the book. A non-zero version makes the delete conditional on
+// the stored version, so a stale client gets domain.ErrVersionConflict.
+func (s *SQLiteBookRepository) Delete(ctx context.Context, id int, version int) error {
+	res, err := s.Conn.ExecContext(ctx, `DELETE FROM book WHERE id = $1 AND ($2 = 0 OR version = $2)`, id, version)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return notAffected(id, version)
+	}
+	return nil
+}
+
+// Update stores the book and bumps its version. A non-zero book.Version is
+// checked against the stored one, on success book.Version holds the new value.
+// The creation time is kept and the update time is set by the database.
+func (s *SQLiteBookRepository) Update(ctx context.Context, book *domain.Book) error {
+	err := s.Conn.QueryRowContext(ctx, `UPDATE book SET title = $1, content = $2, author_id = $3, updated_at = `+now+`, version = version + 1 `+
+		`WHERE id = $4 AND ($5 = 0 OR version = $5) RETURNING version, created_at, updated_at`,
+		book.Title, book.Content, book.Author.ID, book.ID, book.Version).Scan(&book.Version, &book.CreatedAt, &book.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return notAffected(book.ID, book.Version)
+	}
+	return unknownAuthor(err, book)
+}
+
+func (s *SQLiteBookRepository) GetById(ctx context.Context, id int) (domain.Book, error) {
+	list, err := s.fetch(ctx, selectBooks().Where("b.id = ?", id))
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if len(list) == 0 {
+		return domain.Book{}, fmt.Errorf("book %d: %w", id, domain.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+// Close releases the statements prepared by the repository.
+func (s *SQLiteBookRepository) Close() error {
+	return s.stmts.Close()
+}
+
+// unknownAuthor reports a book referencing a missing author as
+// domain.ErrNotFound.
+func unknownAuthor(err error, book *domain.Book) error {
+	var sqliteErr *driver.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY {
+		return fmt.Errorf("author %d: %w", book.Author.ID, domain.ErrNotFound)
+	}
+	return err
+}
+
+func notAffected(id int, version int) error {
+	if version != 0 {
+		return fmt.Errorf("book %d version %d: %w", id, version, domain.ErrVersionConflict)
+	}
+	return fmt.Errorf("book %d: %w", id, domain.ErrNotFound)
+}
@@ -0,0 +1,19 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/repotest"
+)
+
+func open(t *testing.T, authors ...string) domain.BookRepository {
+	repo := NewSQLiteBookRepository(repotest.SQLite(t, authors...))
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestContract(t *testing.T) {
+	repotest.BookRepository(t, open)
+	repotest.BookAuthors(t, open)
+}
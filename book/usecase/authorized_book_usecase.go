@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// authorizedBookUseCase checks the permission of the caller before every
+// operation of the wrapped use case, so every delivery is covered.
+type authorizedBookUseCase struct {
+	next       domain.BookUseCase
+	authorizer domain.Authorizer
+}
+
+func NewAuthorizedBookUseCase(next domain.BookUseCase, authorizer domain.Authorizer) domain.BookUseCase {
+	return &authorizedBookUseCase{next: next, authorizer: authorizer}
+}
+
+func (a *authorizedBookUseCase) Fetch(ctx context.Context, num int, offset int) ([]domain.Book, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadBooks); err != nil {
+		return nil, err
+	}
+	return a.next.Fetch(ctx, num, offset)
+}
+
+func (a *authorizedBookUseCase) Add(ctx context.Context, book *domain.Book) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteBooks); err != nil {
+		return err
+	}
+	return a.next.Add(ctx, book)
+}
+
+func (a *authorizedBookUseCase) Delete(ctx context.Context, id int, version int) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionDeleteBooks); err != nil {
+		return err
+	}
+	return a.next.Delete(ctx, id, version)
+}
+
+func (a *authorizedBookUseCase) Update(ctx context.Context, book *domain.Book) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteBooks); err != nil {
+		return err
+	}
+	return a.next.Update(ctx, book)
+}
+
+func (a *authorizedBookUseCase) Patch(ctx context.Context, id int, version int, patchType domain.PatchType, patch []byte) (domain.Book, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteBooks); err != nil {
+		return domain.Book{}, err
+	}
+	return a.next.Patch(ctx, id, version, patchType, patch)
+}
+
+func (a *authorizedBookUseCase) GetById(ctx context.Context, id int) (domain.Book, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadBooks); err != nil {
+		return domain.Book{}, err
+	}
+	return a.next.GetById(ctx, id)
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+)
+
+func TestAuthorizedDelete(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+	mockAuthorizer := new(mocks.Authorizer)
+
+	mockAuthorizer.On("Authorize", mock.Anything, domain.PermissionDeleteBooks).Return(nil).Once()
+	mockUCase.On("Delete", mock.Anything, 14, 0).Return(nil).Once()
+
+	usecase := NewAuthorizedBookUseCase(mockUCase, mockAuthorizer)
+	err := usecase.Delete(context.TODO(), 14, 0)
+
+	assert.NoError(t, err)
+	mockAuthorizer.AssertExpectations(t)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestAuthorizedForbidden(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+	mockAuthorizer := new(mocks.Authorizer)
+
+	mockAuthorizer.On("Authorize", mock.Anything, domain.PermissionWriteBooks).Return(domain.ErrForbidden).Once()
+
+	usecase := NewAuthorizedBookUseCase(mockUCase, mockAuthorizer)
+	err := usecase.Add(context.TODO(), &domain.Book{Title: "Hello", Content: "World"})
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	mockAuthorizer.AssertExpectations(t)
+	mockUCase.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
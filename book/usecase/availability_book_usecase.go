@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+)
+
+// availabilityBookUseCase embeds the copy counts into the books read through
+// the wrapped use case, limited to the branch of the context if it carries one.
+type availabilityBookUseCase struct {
+	domain.BookUseCase
+	copyRepo domain.CopyRepository
+	timeouts timeout.Timeouts
+}
+
+func NewAvailabilityBookUseCase(next domain.BookUseCase, c domain.CopyRepository, timeouts timeout.Timeouts) domain.BookUseCase {
+	return &availabilityBookUseCase{BookUseCase: next, copyRepo: c, timeouts: timeouts}
+}
+
+func (a *availabilityBookUseCase) Fetch(c context.Context, num int, offset int) ([]domain.Book, error) {
+	res, err := a.BookUseCase.Fetch(c, num, offset)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.embed(c, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (a *availabilityBookUseCase) GetById(c context.Context, id int) (domain.Book, error) {
+	res, err := a.BookUseCase.GetById(c, id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	books := []domain.Book{res}
+	if err = a.embed(c, books); err != nil {
+		return domain.Book{}, err
+	}
+	return books[0], nil
+}
+
+// embed looks the availability of every book up with a single query.
+func (a *availabilityBookUseCase) embed(c context.Context, books []domain.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+	ctxt, cancel := context.WithTimeout(c, a.timeouts.Of("availability"))
+	defer cancel()
+
+	ids := make([]int, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	branchID, _ := domain.BranchFromContext(c)
+	availability, err := a.copyRepo.Availability(ctxt, ids, branchID)
+	if err != nil {
+		return err
+	}
+	for i := range books {
+		counts := availability[books[i].ID]
+		books[i].Availability = &counts
+	}
+	return nil
+}
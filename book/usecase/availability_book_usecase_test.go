@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/mocks"
+)
+
+func TestAvailabilityFetch(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+	mockCopyRepo := new(mocks.CopyRepository)
+
+	mockUCase.On("Fetch", mock.Anything, 10, 0).Return([]domain.Book{{ID: 1}, {ID: 2}}, nil).Once()
+	mockCopyRepo.On("Availability", mock.Anything, []int{1, 2}, 0).
+		Return(map[int]domain.Availability{1: {Total: 2, Available: 1}}, nil).Once()
+
+	books, err := NewAvailabilityBookUseCase(mockUCase, mockCopyRepo, timeout.Every(time.Second)).Fetch(context.TODO(), 10, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Availability{Total: 2, Available: 1}, books[0].Availability)
+	assert.Equal(t, &domain.Availability{}, books[1].Availability)
+	mockCopyRepo.AssertExpectations(t)
+}
+
+func TestAvailabilityOfBranch(t *testing.T) {
+	mockUCase := new(mocks.BookUseCase)
+	mockCopyRepo := new(mocks.CopyRepository)
+
+	mockUCase.On("GetById", mock.Anything, 1).Return(domain.Book{ID: 1}, nil).Once()
+	mockCopyRepo.On("Availability", mock.Anything, []int{1}, 2).
+		Return(map[int]domain.Availability{1: {Total: 1, Available: 1}}, nil).Once()
+
+	ctx := domain.NewContextWithBranch(context.TODO(), 2)
+	book, err := NewAvailabilityBookUseCase(mockUCase, mockCopyRepo, timeout.Every(time.Second)).GetById(ctx, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Availability{Total: 1, Available: 1}, book.Availability)
+	mockCopyRepo.AssertExpectations(t)
+}
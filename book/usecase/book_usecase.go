@@ -2,29 +2,45 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
-	"time"
+	"reflect"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"gopkg.in/go-playground/validator.v9"
 
 	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/i18n"
+	"github.com/bxcodec/library/internal/timeout"
 	"github.com/bxcodec/library/mail"
 	mb "github.com/bxcodec/library/message_broker"
+	"github.com/bxcodec/library/validation"
 )
 
 type bookUseCase struct {
-	bookRepo       domain.BookRepository
-	authorRepo     domain.AuthorRepository
-	messageBroker  mb.MessageBroker
-	contextTimeout time.Duration
-	mailService    mail.Sender
+	bookRepo      domain.BookRepository
+	authorRepo    domain.AuthorRepository
+	unitOfWork    domain.UnitOfWork
+	messageBroker mb.MessageBroker
+	timeouts      timeout.Timeouts
+	mailService   mail.Sender
+	validator     *validator.Validate
 }
 
-func NewBookUseCase(b domain.BookRepository, ar domain.AuthorRepository, mb mb.MessageBroker, mail mail.Sender, timeout time.Duration) domain.BookUseCase {
+// NewBookUseCase returns the book use case reading with b and ar. The changes
+// reading books before writing them run in units of work of uow.
+func NewBookUseCase(b domain.BookRepository, ar domain.AuthorRepository, uow domain.UnitOfWork, mb mb.MessageBroker, mail mail.Sender, timeouts timeout.Timeouts) domain.BookUseCase {
 	return &bookUseCase{
-		bookRepo:       b,
-		authorRepo:     ar,
-		messageBroker:  mb,
-		mailService:    mail,
-		contextTimeout: timeout,
+		bookRepo:      b,
+		authorRepo:    ar,
+		unitOfWork:    uow,
+		messageBroker: mb,
+		mailService:   mail,
+		timeouts:      timeouts,
+		validator:     validation.Validator(),
 	}
 }
 
@@ -32,94 +48,215 @@ func (b *bookUseCase) Fetch(c context.Context, num int, offset int) ([]domain.Bo
 	if num == 0 {
 		num = 10
 	}
-	ctxt, cancel := context.WithTimeout(c, b.contextTimeout)
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("fetch"))
 	defer cancel()
 
 	res, err := b.bookRepo.Fetch(ctxt, num, offset)
 	if err != nil {
 		return nil, err
 	}
+	if err = loadAuthors(ctxt, b.authorRepo, res); err != nil {
+		return nil, err
+	}
 
 	for _, book := range res {
-		b.publishToMsgBrokerAndSendEmail(mb.FETCH, book.Content)
+		b.publishToMsgBrokerAndSendEmail(c, mb.FETCH, book)
 	}
 	return res, err
 }
 
 func (b *bookUseCase) Add(c context.Context, book *domain.Book) error {
-	ctxt, cancel := context.WithTimeout(c, b.contextTimeout)
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("add"))
 	defer cancel()
 
-	existingBook, err := b.getById(ctxt, book.ID)
-	if existingBook != (domain.Book{}) {
-		return domain.ErrConflict
-	}
-	if err = b.bookRepo.Add(ctxt, book); err != nil {
+	err := b.unitOfWork.Do(domain.NewContextWithPrimary(ctxt), func(ctx context.Context, repos domain.Repositories) error {
+		existingBook, _ := getById(ctx, repos, book.ID)
+		if existingBook != (domain.Book{}) {
+			return domain.ErrConflict
+		}
+		return repos.Books.Add(ctx, book)
+	})
+	if err != nil {
 		return err
 	}
-	b.publishToMsgBrokerAndSendEmail(mb.ADD, book.Content)
+	b.publishToMsgBrokerAndSendEmail(c, mb.ADD, *book)
 	return nil
 }
 
-func (b *bookUseCase) Delete(c context.Context, id int) error {
-	ctxt, cancel := context.WithTimeout(c, b.contextTimeout)
+func (b *bookUseCase) Delete(c context.Context, id int, version int) error {
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("delete"))
 	defer cancel()
 
-	existingBook, err := b.getById(c, id)
+	var existingBook domain.Book
+	err := b.unitOfWork.Do(domain.NewContextWithPrimary(ctxt), func(ctx context.Context, repos domain.Repositories) (err error) {
+		existingBook, err = getById(ctx, repos, id)
+		if err != nil {
+			return err
+		}
+		if existingBook == (domain.Book{}) {
+			return domain.ErrNotFound
+		}
+		if version != 0 && existingBook.Version != version {
+			return fmt.Errorf("book %d version %d: %w", id, version, domain.ErrVersionConflict)
+		}
+		return repos.Books.Delete(ctx, existingBook.ID, version)
+	})
 	if err != nil {
 		return err
 	}
-	if existingBook == (domain.Book{}) {
-		return domain.ErrNotFound
-	}
-	if err = b.bookRepo.Delete(ctxt, existingBook.ID); err != nil {
-		return err
-	}
-	b.publishToMsgBrokerAndSendEmail(mb.DELETE, existingBook.Content)
+	b.publishToMsgBrokerAndSendEmail(c, mb.DELETE, existingBook)
 	return nil
 }
 
 func (b *bookUseCase) Update(c context.Context, book *domain.Book) error {
-	ctxt, cancel := context.WithTimeout(c, b.contextTimeout)
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("update"))
 	defer cancel()
 
-	if err := b.bookRepo.Update(ctxt, book); err != nil {
+	err := b.unitOfWork.Do(domain.NewContextWithPrimary(ctxt), func(ctx context.Context, repos domain.Repositories) error {
+		return repos.Books.Update(ctx, book)
+	})
+	if err != nil {
 		return err
 	}
 
-	b.publishToMsgBrokerAndSendEmail(mb.UPDATE, book.Content)
+	b.publishToMsgBrokerAndSendEmail(c, mb.UPDATE, *book)
 	return nil
 }
 
+// Patch applies a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) document
+// to the stored book, read from the primary. The id, version and timestamps of
+// the book can not be patched and the author can only be replaced by id.
+func (b *bookUseCase) Patch(c context.Context, id int, version int, patchType domain.PatchType, patch []byte) (domain.Book, error) {
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("patch"))
+	defer cancel()
+
+	var patched domain.Book
+	var fields []string
+	err := b.unitOfWork.Do(domain.NewContextWithPrimary(ctxt), func(ctx context.Context, repos domain.Repositories) error {
+		existingBook, err := getById(ctx, repos, id)
+		if err != nil {
+			return err
+		}
+		if version != 0 && existingBook.Version != version {
+			return fmt.Errorf("book %d version %d: %w", id, version, domain.ErrVersionConflict)
+		}
+
+		patched, err = applyPatch(existingBook, patchType, patch)
+		if err != nil {
+			return err
+		}
+		patched.ID = existingBook.ID
+		patched.Version = existingBook.Version
+		patched.CreatedAt = existingBook.CreatedAt
+		patched.UpdatedAt = existingBook.UpdatedAt
+		patched.Availability = existingBook.Availability
+		if patched.Author.ID == existingBook.Author.ID {
+			patched.Author = existingBook.Author
+		} else if patched.Author, err = repos.Authors.GetById(ctx, patched.Author.ID); errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+		} else if err != nil {
+			return err
+		}
+		if err = b.validator.Struct(patched); err != nil {
+			return err
+		}
+
+		fields, err = changedFields(existingBook, patched)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			patched = existingBook
+			return nil
+		}
+		if err = repos.Books.Update(ctx, &patched); err != nil {
+			return err
+		}
+		// the repository sets the time of the update
+		fields = append(fields, "updated_at")
+		sort.Strings(fields)
+		return nil
+	})
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if len(fields) == 0 {
+		return patched, nil
+	}
+
+	b.publishEvent(c, mb.Event{Content: patched.Content, Subject: string(mb.UPDATE), Fields: fields, BookID: patched.ID})
+	return patched, nil
+}
+
 func (b *bookUseCase) GetById(ctx context.Context, id int) (domain.Book, error) {
-	res, err := b.getById(ctx, id)
+	res, err := b.getById(ctx, domain.Repositories{Books: b.bookRepo, Authors: b.authorRepo}, id)
 
 	if err != nil {
 		return domain.Book{}, err
 	}
-	b.publishToMsgBrokerAndSendEmail(mb.GetById, res.Content)
+	b.publishToMsgBrokerAndSendEmail(ctx, mb.GetById, res)
 	return res, err
 }
 
-func (b *bookUseCase) getById(ctx context.Context, id int) (domain.Book, error) {
-	ctx, cancel := context.WithTimeout(ctx, b.contextTimeout)
+func (b *bookUseCase) getById(ctx context.Context, repos domain.Repositories, id int) (domain.Book, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.timeouts.Of("get_by_id"))
 	defer cancel()
 
-	res, err := b.bookRepo.GetById(ctx, id)
+	return getById(ctx, repos, id)
+}
+
+// getById reads the book with its author from repos.
+func getById(ctx context.Context, repos domain.Repositories, id int) (domain.Book, error) {
+	res, err := repos.Books.GetById(ctx, id)
 	if err != nil {
 		return domain.Book{}, err
 	}
-	resAuthor, err := b.authorRepo.GetById(ctx, res.Author.ID)
-	if err != nil {
+	books := []domain.Book{res}
+	if err = loadAuthors(ctx, repos.Authors, books); err != nil {
 		return domain.Book{}, err
 	}
-	res.Author = resAuthor
+	return books[0], nil
+}
 
-	return res, err
+// loadAuthors hydrates the authors of books with a single query for all of
+// them. Books whose author is missing keep its id only.
+func loadAuthors(ctx context.Context, authorRepo domain.AuthorRepository, books []domain.Book) error {
+	ids := make([]int, 0, len(books))
+	seen := make(map[int]bool, len(books))
+	for _, book := range books {
+		if !seen[book.Author.ID] {
+			seen[book.Author.ID] = true
+			ids = append(ids, book.Author.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	authors, err := authorRepo.FetchByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range books {
+		if author, ok := authors[books[i].Author.ID]; ok {
+			books[i].Author = author
+		}
+	}
+	return nil
 }
 
-func (b *bookUseCase) publishToMsgBrokerAndSendEmail(eventType mb.EventType, content string) {
-	event := mb.Event{Content: content, Subject: string(eventType)}
+// publishToMsgBrokerAndSendEmail sends an event of eventType about book, which
+// caches of other instances use to invalidate it.
+func (b *bookUseCase) publishToMsgBrokerAndSendEmail(ctx context.Context, eventType mb.EventType, book domain.Book) {
+	b.publishEvent(ctx, mb.Event{Content: book.Content, Subject: string(eventType), BookID: book.ID})
+}
+
+func (b *bookUseCase) publishEvent(ctx context.Context, event mb.Event) {
+	event.Locale = i18n.Locale(ctx)
+	if principal, ok := domain.PrincipalFromContext(ctx); ok {
+		event.Actor = principal.Subject
+	}
+	event.Tenant, _ = domain.TenantFromContext(ctx)
 	err := b.messageBroker.Send(event)
 	if err != nil {
 		log.Println(err.Error())
@@ -145,3 +282,64 @@ func (b *bookUseCase) sendToMail(emailChan chan []byte) {
 		}
 	}
 }
+
+func applyPatch(book domain.Book, patchType domain.PatchType, patch []byte) (domain.Book, error) {
+	original, err := json.Marshal(book)
+	if err != nil {
+		return domain.Book{}, err
+	}
+
+	var modified []byte
+	switch patchType {
+	case domain.MergePatch:
+		modified, err = jsonpatch.MergePatch(original, patch)
+	case domain.JSONPatch:
+		var operations jsonpatch.Patch
+		if operations, err = jsonpatch.DecodePatch(patch); err == nil {
+			modified, err = operations.Apply(original)
+		}
+	default:
+		return domain.Book{}, fmt.Errorf("%w: unknown patch type %q", domain.ErrInvalidPatch, patchType)
+	}
+	if err != nil {
+		return domain.Book{}, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+	}
+
+	var res domain.Book
+	if err = json.Unmarshal(modified, &res); err != nil {
+		return domain.Book{}, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+	}
+	return res, nil
+}
+
+// changedFields lists the JSON names of the book fields that differ between
+// the two books.
+func changedFields(before, after domain.Book) ([]string, error) {
+	beforeFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0)
+	for name, value := range afterFields {
+		if !reflect.DeepEqual(beforeFields[name], value) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func toFields(book domain.Book) (map[string]interface{}, error) {
+	raw, err := json.Marshal(book)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	err = json.Unmarshal(raw, &fields)
+	return fields, err
+}
@@ -5,11 +5,16 @@ import (
 	"testing"
 	"time"
 
+	_author_memory "github.com/bxcodec/library/author/repository/memory"
+	_book_memory "github.com/bxcodec/library/book/repository/memory"
 	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
 	"github.com/bxcodec/library/message_broker"
 	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/transaction"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGet(t *testing.T) {
@@ -25,18 +30,21 @@ func TestGet(t *testing.T) {
 	mockEvent := message_broker.Event{
 		Content: mockBook.Content,
 		Subject: "fetch.sql",
+		BookID:  mockBook.ID,
 	}
 
 	mockRabbitMq := new(mocks.MessageBroker)
 	mockBookRepo := new(mocks.BookRepository)
 	mockMailUseCase := new(mocks.MailService)
 
-	mockBookRepo.On("Fetch", mock.Anything, mock.AnythingOfType("int")).
+	mockBookRepo.On("Fetch", mock.Anything, mock.AnythingOfType("int"), mock.AnythingOfType("int")).
 		Return(mockListOfBook, nil).Once()
 	mockRabbitMq.On("Send", mockEvent).Return(nil).Once()
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
 
 	mockAuthorRepo := new(mocks.AuthorRepository)
-	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, mockRabbitMq, mockMailUseCase, time.Second*2)
+	mockAuthorRepo.On("FetchByIDs", mock.Anything, []int{0}).Return(map[int]domain.Author{}, nil).Once()
+	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
 	fetch, err := usecase.Fetch(context.TODO(), 1, 0)
 
 	assert.NoError(t, err)
@@ -57,6 +65,7 @@ func TestUpdate(t *testing.T) {
 	mockEvent := message_broker.Event{
 		Content: mockBook.Content,
 		Subject: "update.sql",
+		BookID:  mockBook.ID,
 	}
 
 	mockBookRepo := new(mocks.BookRepository)
@@ -66,8 +75,9 @@ func TestUpdate(t *testing.T) {
 
 	mockBookRepo.On("Update", mock.Anything, &mockBook).Once().Return(nil)
 	mockRabbitMq.On("Send", mockEvent).Return(nil).Once()
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
 
-	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, mockRabbitMq, mockMailUseCase, time.Second*2)
+	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
 	err := usecase.Update(context.TODO(), &mockBook)
 
 	assert.NoError(t, err)
@@ -90,6 +100,7 @@ func TestDelete(t *testing.T) {
 	mockEvent := message_broker.Event{
 		Content: mockBook.Content,
 		Subject: "delete.sql",
+		BookID:  mockBook.ID,
 	}
 
 	mockBookRepo := new(mocks.BookRepository)
@@ -98,12 +109,13 @@ func TestDelete(t *testing.T) {
 	mockMailUseCase := new(mocks.MailService)
 
 	mockBookRepo.On("GetById", mock.Anything, mock.AnythingOfType("int")).Return(mockBook, nil).Once()
-	mockAuthorRepo.On("GetById", mock.Anything, mock.AnythingOfType("int")).Return(authorMock, nil).Once()
-	mockBookRepo.On("Delete", mock.Anything, mock.AnythingOfType("int")).Return(nil).Once()
+	mockAuthorRepo.On("FetchByIDs", mock.Anything, []int{0}).Return(map[int]domain.Author{0: authorMock}, nil).Once()
+	mockBookRepo.On("Delete", mock.Anything, mock.AnythingOfType("int"), 0).Return(nil).Once()
 	mockRabbitMq.On("Send", mockEvent).Return(nil).Once()
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
 
-	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, mockRabbitMq, mockMailUseCase, time.Second*2)
-	err := usecase.Delete(context.TODO(), mockBook.ID)
+	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
+	err := usecase.Delete(context.TODO(), mockBook.ID, 0)
 
 	assert.NoError(t, err)
 	mockBookRepo.AssertExpectations(t)
@@ -111,3 +123,335 @@ func TestDelete(t *testing.T) {
 	mockRabbitMq.AssertExpectations(t)
 	mockMailUseCase.AssertExpectations(t)
 }
+
+func TestUpdateRecordsActor(t *testing.T) {
+	mockBook := domain.Book{
+		ID:      14,
+		Title:   "Hello",
+		Content: "World",
+	}
+
+	mockEvent := message_broker.Event{
+		Content: mockBook.Content,
+		Subject: "update.sql",
+		BookID:  mockBook.ID,
+		Actor:   "user-1",
+	}
+
+	mockBookRepo := new(mocks.BookRepository)
+	mockMailUseCase := new(mocks.MailService)
+	mockRabbitMq := new(mocks.MessageBroker)
+	mockAuthorRepo := new(mocks.AuthorRepository)
+
+	mockBookRepo.On("Update", mock.Anything, &mockBook).Once().Return(nil)
+	mockRabbitMq.On("Send", mockEvent).Return(nil).Once()
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
+
+	ctx := domain.NewContextWithPrincipal(context.TODO(), domain.Principal{Subject: "user-1"})
+	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
+	err := usecase.Update(ctx, &mockBook)
+
+	assert.NoError(t, err)
+	mockBookRepo.AssertExpectations(t)
+	mockRabbitMq.AssertExpectations(t)
+}
+
+func TestUpdateScopesEventToTenant(t *testing.T) {
+	mockBook := domain.Book{
+		ID:      14,
+		Title:   "Hello",
+		Content: "World",
+	}
+
+	mockEvent := message_broker.Event{
+		Content: mockBook.Content,
+		Subject: "update.sql",
+		BookID:  mockBook.ID,
+		Tenant:  "central",
+	}
+
+	mockBookRepo := new(mocks.BookRepository)
+	mockMailUseCase := new(mocks.MailService)
+	mockRabbitMq := new(mocks.MessageBroker)
+	mockAuthorRepo := new(mocks.AuthorRepository)
+
+	ctx := domain.NewContextWithTenant(context.TODO(), "central")
+	mockBookRepo.On("Update", mock.MatchedBy(func(c context.Context) bool {
+		tenantID, _ := domain.TenantFromContext(c)
+		return tenantID == "central"
+	}), &mockBook).Once().Return(nil)
+	mockRabbitMq.On("Send", mockEvent).Return(nil).Once()
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
+
+	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
+	err := usecase.Update(ctx, &mockBook)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "central.update.sql", mockEvent.RoutingKey())
+	mockBookRepo.AssertExpectations(t)
+	mockRabbitMq.AssertExpectations(t)
+}
+
+func TestDeleteVersionConflict(t *testing.T) {
+	mockBook := domain.Book{
+		ID:      14,
+		Title:   "Hello",
+		Content: "World",
+		Version: 3,
+	}
+
+	mockBookRepo := new(mocks.BookRepository)
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockRabbitMq := new(mocks.MessageBroker)
+	mockMailUseCase := new(mocks.MailService)
+
+	mockBookRepo.On("GetById", mock.Anything, mockBook.ID).Return(mockBook, nil).Once()
+	mockAuthorRepo.On("FetchByIDs", mock.Anything, []int{0}).Return(map[int]domain.Author{}, nil).Once()
+
+	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
+	err := usecase.Delete(context.TODO(), mockBook.ID, 2)
+
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	mockBookRepo.AssertExpectations(t)
+	mockRabbitMq.AssertExpectations(t)
+}
+
+func TestPatch(t *testing.T) {
+	mockBook := domain.Book{
+		ID:      14,
+		Title:   "Hello",
+		Content: "World",
+		Author:  domain.Author{ID: 1, Name: "TestAuthor"},
+		Version: 2,
+	}
+	patchedBook := mockBook
+	patchedBook.Title = "Bye"
+
+	mockEvent := message_broker.Event{
+		Content: mockBook.Content,
+		Subject: "update.sql",
+		Fields:  []string{"title", "updated_at"},
+		BookID:  mockBook.ID,
+	}
+
+	tests := []struct {
+		name      string
+		patchType domain.PatchType
+		patch     string
+	}{
+		{name: "merge patch", patchType: domain.MergePatch, patch: `{"title": "Bye", "id": 99}`},
+		{name: "json patch", patchType: domain.JSONPatch, patch: `[{"op": "replace", "path": "/title", "value": "Bye"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBookRepo := new(mocks.BookRepository)
+			mockAuthorRepo := new(mocks.AuthorRepository)
+			mockRabbitMq := new(mocks.MessageBroker)
+			mockMailUseCase := new(mocks.MailService)
+
+			mockBookRepo.On("GetById", mock.Anything, mockBook.ID).Return(mockBook, nil).Once()
+			mockAuthorRepo.On("FetchByIDs", mock.Anything, []int{mockBook.Author.ID}).
+				Return(map[int]domain.Author{mockBook.Author.ID: mockBook.Author}, nil).Once()
+			mockBookRepo.On("Update", mock.Anything, &patchedBook).Return(nil).Once()
+			mockRabbitMq.On("Send", mockEvent).Return(nil).Once()
+			mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
+
+			usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
+			book, err := usecase.Patch(context.TODO(), mockBook.ID, mockBook.Version, tt.patchType, []byte(tt.patch))
+
+			assert.NoError(t, err)
+			assert.Equal(t, patchedBook, book)
+			mockBookRepo.AssertExpectations(t)
+			mockRabbitMq.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPatchInvalid(t *testing.T) {
+	mockBook := domain.Book{
+		ID:      14,
+		Title:   "Hello",
+		Content: "World",
+	}
+
+	mockBookRepo := new(mocks.BookRepository)
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockRabbitMq := new(mocks.MessageBroker)
+	mockMailUseCase := new(mocks.MailService)
+
+	mockBookRepo.On("GetById", mock.Anything, mockBook.ID).Return(mockBook, nil)
+	mockAuthorRepo.On("FetchByIDs", mock.Anything, []int{0}).Return(map[int]domain.Author{}, nil)
+
+	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
+
+	_, err := usecase.Patch(context.TODO(), mockBook.ID, 0, domain.JSONPatch, []byte(`[{"op": "remove", "path": "/title"}]`))
+	assert.Error(t, err)
+
+	_, err = usecase.Patch(context.TODO(), mockBook.ID, 0, domain.JSONPatch, []byte(`[{"op": "test", "path": "/title", "value": "Bye"}]`))
+	assert.ErrorIs(t, err, domain.ErrInvalidPatch)
+
+	mockBookRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	mockRabbitMq.AssertExpectations(t)
+}
+
+func TestFetchLoadsAuthorsOnce(t *testing.T) {
+	tolkien := domain.Author{ID: 1, Name: "J. R. R. Tolkien"}
+	lewis := domain.Author{ID: 2, Name: "C. S. Lewis"}
+	books := []domain.Book{
+		{ID: 1, Title: "The Hobbit", Author: domain.Author{ID: 1}},
+		{ID: 2, Title: "The Silmarillion", Author: domain.Author{ID: 1}},
+		{ID: 3, Title: "Perelandra", Author: domain.Author{ID: 2}},
+	}
+
+	mockRabbitMq := new(mocks.MessageBroker)
+	mockBookRepo := new(mocks.BookRepository)
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockMailUseCase := new(mocks.MailService)
+
+	mockBookRepo.On("Fetch", mock.Anything, 3, 0).Return(books, nil).Once()
+	mockAuthorRepo.On("FetchByIDs", mock.Anything, []int{1, 2}).
+		Return(map[int]domain.Author{1: tolkien, 2: lewis}, nil).Once()
+	mockRabbitMq.On("Send", mock.Anything).Return(nil)
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
+
+	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
+	fetch, err := usecase.Fetch(context.TODO(), 3, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, tolkien, fetch[0].Author)
+	assert.Equal(t, tolkien, fetch[1].Author)
+	assert.Equal(t, lewis, fetch[2].Author)
+	mockBookRepo.AssertExpectations(t)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func BenchmarkFetch(b *testing.B) {
+	books := make([]domain.Book, 100)
+	authors := make(map[int]domain.Author)
+	for i := range books {
+		books[i] = domain.Book{ID: i + 1, Title: "Book", Author: domain.Author{ID: i%10 + 1}}
+		authors[i%10+1] = domain.Author{ID: i%10 + 1, Name: "Author"}
+	}
+
+	mockRabbitMq := new(mocks.MessageBroker)
+	mockBookRepo := new(mocks.BookRepository)
+	mockAuthorRepo := new(mocks.AuthorRepository)
+	mockMailUseCase := new(mocks.MailService)
+
+	mockBookRepo.On("Fetch", mock.Anything, 100, 0).Return(books, nil)
+	mockAuthorRepo.On("FetchByIDs", mock.Anything, mock.Anything).Return(authors, nil)
+	mockRabbitMq.On("Send", mock.Anything).Return(nil)
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
+
+	usecase := NewBookUseCase(mockBookRepo, mockAuthorRepo, transaction.None(domain.Repositories{Books: mockBookRepo, Authors: mockAuthorRepo}), mockRabbitMq, mockMailUseCase, timeout.Every(time.Second*2))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := usecase.Fetch(context.TODO(), 100, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	queries := len(mockBookRepo.Calls) + len(mockAuthorRepo.Calls)
+	b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+}
+
+func TestPatchStoresBook(t *testing.T) {
+	author := domain.Author{ID: 1, Name: "Iman Tumorang"}
+	bookRepo := _book_memory.NewMemoryBookRepository()
+	authorRepo := _author_memory.NewMemoryAuthorRepository(author)
+	mockRabbitMq := new(mocks.MessageBroker)
+	mockRabbitMq.On("Send", mock.Anything).Return(nil)
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
+
+	usecase := NewBookUseCase(bookRepo, authorRepo, transaction.None(domain.Repositories{Books: bookRepo, Authors: authorRepo}), mockRabbitMq, new(mocks.MailService), timeout.Every(time.Second*2))
+	book := domain.Book{Title: "Hello", Content: "World", Author: domain.Author{ID: 1}}
+	assert.NoError(t, usecase.Add(context.TODO(), &book))
+
+	patched, err := usecase.Patch(context.TODO(), book.ID, book.Version, domain.MergePatch, []byte(`{"title": "Bye"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, patched.Version)
+
+	stored, err := usecase.GetById(context.TODO(), book.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bye", stored.Title)
+	assert.Equal(t, author, stored.Author)
+
+	_, err = usecase.Patch(context.TODO(), book.ID, book.Version, domain.MergePatch, []byte(`{"title": "Stale"}`))
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+}
+
+func TestPatchKeepsTimestamps(t *testing.T) {
+	author := domain.Author{ID: 1, Name: "Iman Tumorang"}
+	bookRepo := _book_memory.NewMemoryBookRepository()
+	authorRepo := _author_memory.NewMemoryAuthorRepository(author)
+	mockRabbitMq := new(mocks.MessageBroker)
+	mockRabbitMq.On("Send", mock.MatchedBy(func(e message_broker.Event) bool { return e.Subject == string(message_broker.ADD) })).Return(nil).Once()
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
+
+	usecase := NewBookUseCase(bookRepo, authorRepo, transaction.None(domain.Repositories{Books: bookRepo, Authors: authorRepo}), mockRabbitMq, new(mocks.MailService), timeout.Every(time.Second*2))
+	book := domain.Book{Title: "Hello", Content: "World", Author: domain.Author{ID: 1}}
+	require.NoError(t, usecase.Add(context.TODO(), &book))
+	stored, err := bookRepo.GetById(context.TODO(), book.ID)
+	require.NoError(t, err)
+
+	patched, err := usecase.Patch(context.TODO(), book.ID, 0, domain.MergePatch, []byte(`{"created_at": "2001-01-01T00:00:00Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, stored.CreatedAt, patched.CreatedAt)
+	assert.Equal(t, stored.Version, patched.Version)
+
+	mockRabbitMq.On("Send", mock.MatchedBy(func(e message_broker.Event) bool { return e.Subject == string(message_broker.UPDATE) })).
+		Run(func(args mock.Arguments) {
+			assert.Equal(t, []string{"title", "updated_at"}, args.Get(0).(message_broker.Event).Fields)
+		}).Return(nil).Once()
+	patched, err = usecase.Patch(context.TODO(), book.ID, 0, domain.MergePatch, []byte(`{"title": "Bye", "created_at": "2001-01-01T00:00:00Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, stored.CreatedAt, patched.CreatedAt)
+	updated, err := bookRepo.GetById(context.TODO(), book.ID)
+	require.NoError(t, err)
+	assert.Equal(t, updated.UpdatedAt, patched.UpdatedAt)
+	mockRabbitMq.AssertExpectations(t)
+}
+
+// unitOfWork runs functions with its repositories, recording whether they
+// failed.
+type unitOfWork struct {
+	repos  domain.Repositories
+	errors []error
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos domain.Repositories) error) error {
+	err := fn(ctx, u.repos)
+	u.errors = append(u.errors, err)
+	return err
+}
+
+func TestChangesRunInUnitOfWork(t *testing.T) {
+	author := domain.Author{ID: 1, Name: "Iman Tumorang"}
+	bookRepo := _book_memory.NewMemoryBookRepository()
+	authorRepo := _author_memory.NewMemoryAuthorRepository(author)
+	mockRabbitMq := new(mocks.MessageBroker)
+	mockRabbitMq.On("Send", mock.Anything).Return(nil)
+	mockRabbitMq.On("Receive", mock.Anything).Return(nil).Maybe()
+	uow := &unitOfWork{repos: domain.Repositories{Books: bookRepo, Authors: authorRepo}}
+
+	// The use case has mocks without expectations for repositories, any change
+	// made outside of the unit of work fails the test.
+	usecase := NewBookUseCase(new(mocks.BookRepository), new(mocks.AuthorRepository), uow, mockRabbitMq, new(mocks.MailService), timeout.Every(time.Second*2))
+	book := domain.Book{Title: "Hello", Content: "World", Author: domain.Author{ID: 1}}
+	assert.NoError(t, usecase.Add(context.TODO(), &book))
+	book.Title = "Bye"
+	assert.NoError(t, usecase.Update(context.TODO(), &book))
+	_, err := usecase.Patch(context.TODO(), book.ID, book.Version, domain.MergePatch, []byte(`{"author": {"id": 2}}`))
+	assert.ErrorIs(t, err, domain.ErrInvalidPatch)
+	assert.NoError(t, usecase.Delete(context.TODO(), book.ID, book.Version))
+
+	assert.Len(t, uow.errors, 4)
+	assert.NoError(t, uow.errors[0])
+	assert.NoError(t, uow.errors[1])
+	assert.ErrorIs(t, uow.errors[2], domain.ErrInvalidPatch)
+	assert.NoError(t, uow.errors[3])
+	_, err = bookRepo.GetById(context.TODO(), book.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/validation"
+)
+
+const ID = "id"
+
+type BranchHandler struct {
+	BUseCase  domain.BranchUseCase
+	validator *validator.Validate
+}
+
+// NewBranchHandler registers the branch routes on e, each of them wrapped in
+// middleware.
+func NewBranchHandler(e *echo.Echo, us domain.BranchUseCase, middleware ...echo.MiddlewareFunc) {
+	handler := &BranchHandler{BUseCase: us, validator: validation.Validator()}
+	handler.Register(e.Group("/api/v1", middleware...))
+}
+
+func (h *BranchHandler) Register(g *echo.Group) {
+	g.GET("/branches", h.Fetch)
+	g.GET("/branches/:id", h.GetById)
+	g.POST("/branches", h.Add)
+	g.PUT("/branches/:id", h.Update)
+	g.DELETE("/branches/:id", h.Delete)
+}
+
+func (h BranchHandler) Fetch(c echo.Context) error {
+	branches, err := h.BUseCase.Fetch(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, branches)
+}
+
+func (h BranchHandler) GetById(c echo.Context) error {
+	id, err := branchID(c)
+	if err != nil {
+		return err
+	}
+
+	branch, err := h.BUseCase.GetById(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, branch)
+}
+
+func (h BranchHandler) Add(c echo.Context) error {
+	var branch domain.Branch
+	if err := c.Bind(&branch); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(&branch); err != nil {
+		return err
+	}
+
+	if err := h.BUseCase.Add(c.Request().Context(), &branch); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, branch)
+}
+
+func (h BranchHandler) Update(c echo.Context) error {
+	var branch domain.Branch
+	var err error
+	if err = c.Bind(&branch); err != nil {
+		return err
+	}
+	if branch.ID, err = branchID(c); err != nil {
+		return err
+	}
+	if err = h.validator.Struct(&branch); err != nil {
+		return err
+	}
+
+	if err = h.BUseCase.Update(c.Request().Context(), &branch); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, branch)
+}
+
+func (h BranchHandler) Delete(c echo.Context) error {
+	id, err := branchID(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.BUseCase.Delete(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func branchID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param(ID))
+	if err != nil {
+		return 0, &domain.InvalidIDError{Param: ID, Value: c.Param(ID)}
+	}
+	return id, nil
+}
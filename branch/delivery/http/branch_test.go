@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/validation"
+)
+
+func TestAdd(t *testing.T) {
+	mockUCase := new(mocks.BranchUseCase)
+	mockUCase.On("Add", mock.Anything, &domain.Branch{Code: "EAST", Name: "East Branch"}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Branch).ID = 2 }).
+		Return(nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/branches", strings.NewReader(`{"code":"EAST","name":"East Branch"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := BranchHandler{BUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, handler.Add(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":2`)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDeleteInUse(t *testing.T) {
+	mockUCase := new(mocks.BranchUseCase)
+	mockUCase.On("Delete", mock.Anything, 1).Return(domain.ErrConflict).Once()
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(echo.DELETE, "/api/v1/branches/1", nil), rec)
+	c.SetParamNames(ID)
+	c.SetParamValues("1")
+	handler := BranchHandler{BUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.Delete)(c))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
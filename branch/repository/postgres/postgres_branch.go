@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+)
+
+const (
+	selectBranch = `SELECT id, code, name, address, created_at, updated_at FROM branch`
+
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+type postgresBranchRepository struct {
+	Conn *sql.DB
+}
+
+func NewPostgresBranchRepository(Conn *sql.DB) domain.BranchRepository {
+	return &postgresBranchRepository{Conn}
+}
+
+func (p *postgresBranchRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]domain.Branch, error) {
+	rows, err := p.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make([]domain.Branch, 0)
+	for rows.Next() {
+		branch := domain.Branch{}
+		err = rows.Scan(
+			&branch.ID,
+			&branch.Code,
+			&branch.Name,
+			&branch.Address,
+			&branch.CreatedAt,
+			&branch.UpdatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, branch)
+	}
+	return result, rows.Err()
+}
+
+func (p *postgresBranchRepository) Fetch(ctx context.Context) ([]domain.Branch, error) {
+	return p.fetch(ctx, selectBranch+` ORDER BY code`)
+}
+
+func (p *postgresBranchRepository) GetById(ctx context.Context, id int) (domain.Branch, error) {
+	list, err := p.fetch(ctx, selectBranch+` WHERE id = $1`, id)
+	if err != nil {
+		return domain.Branch{}, err
+	}
+	if len(list) == 0 {
+		return domain.Branch{}, fmt.Errorf("branch %d: %w", id, domain.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+func (p *postgresBranchRepository) Add(ctx context.Context, b *domain.Branch) error {
+	err := p.Conn.QueryRowContext(ctx, `INSERT INTO branch (code, name, address) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at`,
+		b.Code, b.Name, b.Address).Scan(&b.ID, &b.CreatedAt, &b.UpdatedAt)
+	return conflict(err, b)
+}
+
+func (p *postgresBranchRepository) Update(ctx context.Context, b *domain.Branch) error {
+	err := p.Conn.QueryRowContext(ctx, `UPDATE branch SET code = $1, name = $2, address = $3, updated_at = now() `+
+		`WHERE id = $4 RETURNING created_at, updated_at`,
+		b.Code, b.Name, b.Address, b.ID).Scan(&b.CreatedAt, &b.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("branch %d: %w", b.ID, domain.ErrNotFound)
+	}
+	return conflict(err, b)
+}
+
+func (p *postgresBranchRepository) Delete(ctx context.Context, id int) error {
+	res, err := p.Conn.ExecContext(ctx, `DELETE FROM branch WHERE id = $1`, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return fmt.Errorf("branch %d has copies or members: %w", id, domain.ErrConflict)
+	}
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("branch %d: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// conflict reports a duplicate branch code as domain.ErrConflict.
+func conflict(err error, b *domain.Branch) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return fmt.Errorf("branch code %q: %w", b.Code, domain.ErrConflict)
+	}
+	return err
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+)
+
+func TestAddDuplicateCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO branch (code, name, address)`)).WithArgs("MAIN", "Main Library", "").
+		WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "branch_code_key"})
+
+	err = NewPostgresBranchRepository(db).Add(context.TODO(), &domain.Branch{Code: "MAIN", Name: "Main Library"})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestDeleteBranchInUse(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM branch WHERE id = $1`)).WithArgs(1).
+		WillReturnError(&pq.Error{Code: foreignKeyViolation, Constraint: "copy_branch_id_fkey"})
+
+	err = NewPostgresBranchRepository(db).Delete(context.TODO(), 1)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
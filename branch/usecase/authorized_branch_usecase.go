@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// authorizedBranchUseCase checks the permission of the caller before every
+// operation of the wrapped use case. Branches are listed to everyone who may
+// read the catalog.
+type authorizedBranchUseCase struct {
+	next       domain.BranchUseCase
+	authorizer domain.Authorizer
+}
+
+func NewAuthorizedBranchUseCase(next domain.BranchUseCase, authorizer domain.Authorizer) domain.BranchUseCase {
+	return &authorizedBranchUseCase{next: next, authorizer: authorizer}
+}
+
+func (a *authorizedBranchUseCase) Fetch(ctx context.Context) ([]domain.Branch, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadBooks); err != nil {
+		return nil, err
+	}
+	return a.next.Fetch(ctx)
+}
+
+func (a *authorizedBranchUseCase) GetById(ctx context.Context, id int) (domain.Branch, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadBooks); err != nil {
+		return domain.Branch{}, err
+	}
+	return a.next.GetById(ctx, id)
+}
+
+func (a *authorizedBranchUseCase) Add(ctx context.Context, branch *domain.Branch) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteBranches); err != nil {
+		return err
+	}
+	return a.next.Add(ctx, branch)
+}
+
+func (a *authorizedBranchUseCase) Update(ctx context.Context, branch *domain.Branch) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteBranches); err != nil {
+		return err
+	}
+	return a.next.Update(ctx, branch)
+}
+
+func (a *authorizedBranchUseCase) Delete(ctx context.Context, id int) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteBranches); err != nil {
+		return err
+	}
+	return a.next.Delete(ctx, id)
+}
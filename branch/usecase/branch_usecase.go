@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/validation"
+)
+
+type branchUseCase struct {
+	branchRepo domain.BranchRepository
+	timeouts   timeout.Timeouts
+	validator  *validator.Validate
+}
+
+func NewBranchUseCase(b domain.BranchRepository, timeouts timeout.Timeouts) domain.BranchUseCase {
+	return &branchUseCase{
+		branchRepo: b,
+		timeouts:   timeouts,
+		validator:  validation.Validator(),
+	}
+}
+
+func (b *branchUseCase) Fetch(c context.Context) ([]domain.Branch, error) {
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("fetch"))
+	defer cancel()
+
+	return b.branchRepo.Fetch(ctxt)
+}
+
+func (b *branchUseCase) GetById(c context.Context, id int) (domain.Branch, error) {
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("get_by_id"))
+	defer cancel()
+
+	return b.branchRepo.GetById(ctxt, id)
+}
+
+func (b *branchUseCase) Add(c context.Context, branch *domain.Branch) error {
+	normalize(branch)
+	if err := b.validator.Struct(branch); err != nil {
+		return err
+	}
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("add"))
+	defer cancel()
+
+	return b.branchRepo.Add(ctxt, branch)
+}
+
+func (b *branchUseCase) Update(c context.Context, branch *domain.Branch) error {
+	normalize(branch)
+	if err := b.validator.Struct(branch); err != nil {
+		return err
+	}
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("update"))
+	defer cancel()
+
+	return b.branchRepo.Update(ctxt, branch)
+}
+
+func (b *branchUseCase) Delete(c context.Context, id int) error {
+	ctxt, cancel := context.WithTimeout(c, b.timeouts.Of("delete"))
+	defer cancel()
+
+	return b.branchRepo.Delete(ctxt, id)
+}
+
+// normalize stores branch codes upper case, so "main" and "MAIN" collide.
+func normalize(branch *domain.Branch) {
+	branch.Code = strings.ToUpper(strings.TrimSpace(branch.Code))
+	branch.Name = strings.TrimSpace(branch.Name)
+	branch.Address = strings.TrimSpace(branch.Address)
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/mocks"
+)
+
+func TestAddNormalizesCode(t *testing.T) {
+	mockRepo := new(mocks.BranchRepository)
+	mockRepo.On("Add", mock.Anything, &domain.Branch{Code: "EAST", Name: "East Branch"}).Return(nil).Once()
+
+	branch := domain.Branch{Code: " east ", Name: "East Branch"}
+	err := NewBranchUseCase(mockRepo, timeout.Every(time.Second)).Add(context.TODO(), &branch)
+
+	require.NoError(t, err)
+	assert.Equal(t, "EAST", branch.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthorizedAddForbidden(t *testing.T) {
+	mockUCase := new(mocks.BranchUseCase)
+	mockAuthorizer := new(mocks.Authorizer)
+	mockAuthorizer.On("Authorize", mock.Anything, domain.PermissionWriteBranches).Return(domain.ErrForbidden).Once()
+
+	err := NewAuthorizedBranchUseCase(mockUCase, mockAuthorizer).Add(context.TODO(), &domain.Branch{Code: "EAST", Name: "East"})
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	mockUCase.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/validation"
+)
+
+const (
+	ID      = "id"
+	COPY_ID = "copy_id"
+)
+
+type CopyHandler struct {
+	CUseCase  domain.CopyUseCase
+	validator *validator.Validate
+}
+
+// NewCopyHandler registers the routes managing the copies of a book on e,
+// each of them wrapped in middleware.
+func NewCopyHandler(e *echo.Echo, us domain.CopyUseCase, middleware ...echo.MiddlewareFunc) {
+	handler := &CopyHandler{CUseCase: us, validator: validation.Validator()}
+	handler.Register(e.Group("/api/v1", middleware...))
+}
+
+func (h *CopyHandler) Register(g *echo.Group) {
+	g.GET("/books/:id/copies", h.FetchByBook)
+	g.POST("/books/:id/copies", h.Add)
+	g.GET("/books/:id/copies/:copy_id", h.GetById)
+	g.PUT("/books/:id/copies/:copy_id", h.Update)
+	g.DELETE("/books/:id/copies/:copy_id", h.Delete)
+}
+
+func (h CopyHandler) FetchByBook(c echo.Context) error {
+	bookID, err := param(c, ID)
+	if err != nil {
+		return err
+	}
+
+	copies, err := h.CUseCase.FetchByBook(c.Request().Context(), bookID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, copies)
+}
+
+func (h CopyHandler) GetById(c echo.Context) error {
+	bookID, err := param(c, ID)
+	if err != nil {
+		return err
+	}
+	id, err := param(c, COPY_ID)
+	if err != nil {
+		return err
+	}
+
+	bookCopy, err := h.CUseCase.GetById(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	if bookCopy.BookID != bookID {
+		return fmt.Errorf("copy %d of book %d: %w", id, bookID, domain.ErrNotFound)
+	}
+	return c.JSON(http.StatusOK, bookCopy)
+}
+
+func (h CopyHandler) Add(c echo.Context) error {
+	var bookCopy domain.Copy
+	var err error
+	if err = c.Bind(&bookCopy); err != nil {
+		return err
+	}
+	if bookCopy.BookID, err = param(c, ID); err != nil {
+		return err
+	}
+	if err = h.validator.Struct(&bookCopy); err != nil {
+		return err
+	}
+
+	if err = h.CUseCase.Add(c.Request().Context(), &bookCopy); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, bookCopy)
+}
+
+func (h CopyHandler) Update(c echo.Context) error {
+	var bookCopy domain.Copy
+	var err error
+	if err = c.Bind(&bookCopy); err != nil {
+		return err
+	}
+	if bookCopy.BookID, err = param(c, ID); err != nil {
+		return err
+	}
+	if bookCopy.ID, err = param(c, COPY_ID); err != nil {
+		return err
+	}
+	if err = h.validator.Struct(&bookCopy); err != nil {
+		return err
+	}
+
+	if err = h.CUseCase.Update(c.Request().Context(), &bookCopy); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, bookCopy)
+}
+
+func (h CopyHandler) Delete(c echo.Context) error {
+	bookID, err := param(c, ID)
+	if err != nil {
+		return err
+	}
+	id, err := param(c, COPY_ID)
+	if err != nil {
+		return err
+	}
+
+	if err = h.CUseCase.Delete(c.Request().Context(), bookID, id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func param(c echo.Context, name string) (int, error) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil {
+		return 0, &domain.InvalidIDError{Param: name, Value: c.Param(name)}
+	}
+	return id, nil
+}
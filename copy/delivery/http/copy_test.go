@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/validation"
+)
+
+func TestAdd(t *testing.T) {
+	mockUCase := new(mocks.CopyUseCase)
+	mockUCase.On("Add", mock.Anything, &domain.Copy{BookID: 1, Barcode: "0001", ShelfLocation: "A-1", BranchID: 1}).Return(nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/books/1/copies", strings.NewReader(`{"barcode":"0001","shelf_location":"A-1","branch_id":1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(ID)
+	c.SetParamValues("1")
+	handler := CopyHandler{CUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, handler.Add(c))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestGetByIdOfOtherBook(t *testing.T) {
+	mockUCase := new(mocks.CopyUseCase)
+	mockUCase.On("GetById", mock.Anything, 5).Return(domain.Copy{ID: 5, BookID: 2}, nil).Once()
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(echo.GET, "/api/v1/books/1/copies/5", nil), rec)
+	c.SetParamNames(ID, COPY_ID)
+	c.SetParamValues("1", "5")
+	handler := CopyHandler{CUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.GetById)(c))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/replica"
+)
+
+const (
+	selectCopy = `SELECT id, book_id, barcode, condition, shelf_location, branch_id, status, created_at, updated_at FROM copy`
+
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+type postgresCopyRepository struct {
+	Conn *replica.Cluster
+}
+
+// NewPostgresCopyRepository returns the copies of Conn, read from its replicas
+// and written to its primary.
+func NewPostgresCopyRepository(Conn *replica.Cluster) domain.CopyRepository {
+	return &postgresCopyRepository{Conn}
+}
+
+func (p *postgresCopyRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]domain.Copy, error) {
+	rows, err := p.Conn.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make([]domain.Copy, 0)
+	for rows.Next() {
+		c := domain.Copy{}
+		err = rows.Scan(
+			&c.ID,
+			&c.BookID,
+			&c.Barcode,
+			&c.Condition,
+			&c.ShelfLocation,
+			&c.BranchID,
+			&c.Status,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+func (p *postgresCopyRepository) FetchByBook(ctx context.Context, bookID int) ([]domain.Copy, error) {
+	return p.fetch(ctx, selectCopy+` WHERE book_id = $1 ORDER BY id`, bookID)
+}
+
+func (p *postgresCopyRepository) GetById(ctx context.Context, id int) (domain.Copy, error) {
+	list, err := p.fetch(ctx, selectCopy+` WHERE id = $1`, id)
+	if err != nil {
+		return domain.Copy{}, err
+	}
+	if len(list) == 0 {
+		return domain.Copy{}, fmt.Errorf("copy %d: %w", id, domain.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+func (p *postgresCopyRepository) Add(ctx context.Context, c *domain.Copy) error {
+	err := p.Conn.Primary().QueryRowContext(ctx, `INSERT INTO copy (book_id, barcode, condition, shelf_location, branch_id, status) `+
+		`VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`,
+		c.BookID, c.Barcode, c.Condition, c.ShelfLocation, c.BranchID, c.Status).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	return constraint(err, c)
+}
+
+func (p *postgresCopyRepository) Update(ctx context.Context, c *domain.Copy, status domain.CopyStatus) error {
+	err := p.Conn.Primary().QueryRowContext(ctx, `UPDATE copy SET barcode = $1, condition = $2, shelf_location = $3, branch_id = $4, status = $5, updated_at = now() `+
+		`WHERE id = $6 AND status = $7 RETURNING created_at, updated_at`,
+		c.Barcode, c.Condition, c.ShelfLocation, c.BranchID, c.Status, c.ID, status).Scan(&c.CreatedAt, &c.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		if _, err = p.GetById(domain.NewContextWithPrimary(ctx), c.ID); err != nil {
+			return err
+		}
+		return fmt.Errorf("copy %d is no longer %s: %w", c.ID, status, domain.ErrConflict)
+	}
+	return constraint(err, c)
+}
+
+func (p *postgresCopyRepository) Delete(ctx context.Context, id int) error {
+	res, err := p.Conn.Primary().ExecContext(ctx, `DELETE FROM copy WHERE id = $1`, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return fmt.Errorf("copy %d has loans or transfers: %w", id, domain.ErrConflict)
+	}
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("copy %d: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// Availability counts the copies of every book of bookIDs held by the branch,
+// or by every branch when branchID is zero. Books without copies are missing
+// from the result.
+func (p *postgresCopyRepository) Availability(ctx context.Context, bookIDs []int, branchID int) (map[int]domain.Availability, error) {
+	rows, err := p.Conn.Reader(ctx).QueryContext(ctx, `SELECT book_id, count(*), count(*) FILTER (WHERE status = $2) FROM copy `+
+		`WHERE book_id = ANY($1) AND ($3 = 0 OR branch_id = $3) GROUP BY book_id`, pq.Array(bookIDs), domain.CopyAvailable, branchID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make(map[int]domain.Availability)
+	for rows.Next() {
+		var bookID int
+		var availability domain.Availability
+		if err = rows.Scan(&bookID, &availability.Total, &availability.Available); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result[bookID] = availability
+	}
+	return result, rows.Err()
+}
+
+// constraint reports a duplicate barcode as domain.ErrConflict and an unknown
+// book or branch as domain.ErrNotFound.
+func constraint(err error, c *domain.Copy) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return fmt.Errorf("copy barcode %q: %w", c.Barcode, domain.ErrConflict)
+		case foreignKeyViolation:
+			if pqErr.Constraint == "copy_branch_id_fkey" {
+				return fmt.Errorf("branch %d: %w", c.BranchID, domain.ErrNotFound)
+			}
+			return fmt.Errorf("book %d: %w", c.BookID, domain.ErrNotFound)
+		}
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/replica"
+)
+
+func TestAvailability(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"book_id", "count", "count"}).
+		AddRow(1, 2, 1).
+		AddRow(3, 1, 0)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT book_id, count(*), count(*) FILTER (WHERE status = $2) FROM copy WHERE book_id = ANY($1) AND ($3 = 0 OR branch_id = $3) GROUP BY book_id`)).
+		WithArgs(pq.Array([]int{1, 2, 3}), domain.CopyAvailable, 2).
+		WillReturnRows(rows)
+
+	availability, err := NewPostgresCopyRepository(replica.New(db, nil)).Availability(context.TODO(), []int{1, 2, 3}, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[int]domain.Availability{1: {Total: 2, Available: 1}, 3: {Total: 1, Available: 0}}, availability)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddDuplicateBarcode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("INSERT INTO copy").WillReturnError(&pq.Error{Code: uniqueViolation})
+
+	err = NewPostgresCopyRepository(replica.New(db, nil)).Add(context.TODO(), &domain.Copy{BookID: 1, Barcode: "0001"})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestAddUnknownBook(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("INSERT INTO copy").WillReturnError(&pq.Error{Code: foreignKeyViolation})
+
+	err = NewPostgresCopyRepository(replica.New(db, nil)).Add(context.TODO(), &domain.Copy{BookID: 99, Barcode: "0001"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestUpdateChangedByCirculation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	bookCopy := &domain.Copy{ID: 5, BookID: 1, Barcode: "0001", BranchID: 1, Status: domain.CopyRepair}
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE id = $6 AND status = $7`)).
+		WithArgs(bookCopy.Barcode, bookCopy.Condition, bookCopy.ShelfLocation, bookCopy.BranchID, bookCopy.Status, 5, domain.CopyAvailable).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}))
+	mock.ExpectQuery(regexp.QuoteMeta(selectCopy + ` WHERE id = $1`)).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "barcode", "condition", "shelf_location", "branch_id", "status", "created_at", "updated_at"}).
+			AddRow(5, 1, "0001", "", "", 1, domain.CopyOnLoan, time.Now(), time.Now()))
+
+	err = NewPostgresCopyRepository(replica.New(db, nil)).Update(context.TODO(), bookCopy, domain.CopyAvailable)
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
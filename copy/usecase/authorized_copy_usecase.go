@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// authorizedCopyUseCase checks the permission of the caller before every
+// operation of the wrapped use case. Copies are managed with the permissions
+// of their books.
+type authorizedCopyUseCase struct {
+	next       domain.CopyUseCase
+	authorizer domain.Authorizer
+}
+
+func NewAuthorizedCopyUseCase(next domain.CopyUseCase, authorizer domain.Authorizer) domain.CopyUseCase {
+	return &authorizedCopyUseCase{next: next, authorizer: authorizer}
+}
+
+func (a *authorizedCopyUseCase) FetchByBook(ctx context.Context, bookID int) ([]domain.Copy, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadBooks); err != nil {
+		return nil, err
+	}
+	return a.next.FetchByBook(ctx, bookID)
+}
+
+func (a *authorizedCopyUseCase) GetById(ctx context.Context, id int) (domain.Copy, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadBooks); err != nil {
+		return domain.Copy{}, err
+	}
+	return a.next.GetById(ctx, id)
+}
+
+func (a *authorizedCopyUseCase) Add(ctx context.Context, c *domain.Copy) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteBooks); err != nil {
+		return err
+	}
+	return a.next.Add(ctx, c)
+}
+
+func (a *authorizedCopyUseCase) Update(ctx context.Context, c *domain.Copy) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteBooks); err != nil {
+		return err
+	}
+	return a.next.Update(ctx, c)
+}
+
+func (a *authorizedCopyUseCase) Delete(ctx context.Context, bookID int, id int) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionDeleteBooks); err != nil {
+		return err
+	}
+	return a.next.Delete(ctx, bookID, id)
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/validation"
+)
+
+type copyUseCase struct {
+	copyRepo  domain.CopyRepository
+	timeouts  timeout.Timeouts
+	validator *validator.Validate
+}
+
+func NewCopyUseCase(c domain.CopyRepository, timeouts timeout.Timeouts) domain.CopyUseCase {
+	return &copyUseCase{
+		copyRepo:  c,
+		timeouts:  timeouts,
+		validator: validation.Validator(),
+	}
+}
+
+func (u *copyUseCase) FetchByBook(c context.Context, bookID int) ([]domain.Copy, error) {
+	ctxt, cancel := context.WithTimeout(c, u.timeouts.Of("fetch_by_book"))
+	defer cancel()
+
+	return u.copyRepo.FetchByBook(ctxt, bookID)
+}
+
+func (u *copyUseCase) GetById(c context.Context, id int) (domain.Copy, error) {
+	ctxt, cancel := context.WithTimeout(c, u.timeouts.Of("get_by_id"))
+	defer cancel()
+
+	return u.copyRepo.GetById(ctxt, id)
+}
+
+// Add stores a new copy, which can not start on loan or on hold.
+func (u *copyUseCase) Add(c context.Context, bookCopy *domain.Copy) error {
+	bookCopy.Barcode = strings.TrimSpace(bookCopy.Barcode)
+	if bookCopy.Status == "" {
+		bookCopy.Status = domain.CopyAvailable
+	}
+	if err := u.validator.Struct(bookCopy); err != nil {
+		return err
+	}
+	if bookCopy.Status.Circulating() {
+		return fmt.Errorf("copy %q can only be %s by circulation: %w", bookCopy.Barcode, bookCopy.Status, domain.ErrConflict)
+	}
+	ctxt, cancel := context.WithTimeout(c, u.timeouts.Of("add"))
+	defer cancel()
+
+	return u.copyRepo.Add(ctxt, bookCopy)
+}
+
+// Update changes a copy of the book bookCopy.BookID. Only loans and holds put
+// a copy on and off loan or hold, so such a copy keeps its status.
+func (u *copyUseCase) Update(c context.Context, bookCopy *domain.Copy) error {
+	bookCopy.Barcode = strings.TrimSpace(bookCopy.Barcode)
+	ctxt, cancel := context.WithTimeout(domain.NewContextWithPrimary(c), u.timeouts.Of("update"))
+	defer cancel()
+
+	existing, err := u.getOfBook(ctxt, bookCopy.BookID, bookCopy.ID)
+	if err != nil {
+		return err
+	}
+	if bookCopy.Status == "" {
+		bookCopy.Status = existing.Status
+	}
+	if err = u.validator.Struct(bookCopy); err != nil {
+		return err
+	}
+	if existing.Status != bookCopy.Status && (existing.Status.Circulating() || bookCopy.Status.Circulating()) {
+		return fmt.Errorf("copy %d status %s can only change by circulation: %w", bookCopy.ID, existing.Status, domain.ErrConflict)
+	}
+	return u.copyRepo.Update(ctxt, bookCopy, existing.Status)
+}
+
+func (u *copyUseCase) Delete(c context.Context, bookID int, id int) error {
+	ctxt, cancel := context.WithTimeout(domain.NewContextWithPrimary(c), u.timeouts.Of("delete"))
+	defer cancel()
+
+	existing, err := u.getOfBook(ctxt, bookID, id)
+	if err != nil {
+		return err
+	}
+	if existing.Status.Circulating() {
+		return fmt.Errorf("copy %d is %s: %w", id, existing.Status, domain.ErrConflict)
+	}
+	return u.copyRepo.Delete(ctxt, id)
+}
+
+func (u *copyUseCase) getOfBook(ctx context.Context, bookID int, id int) (domain.Copy, error) {
+	existing, err := u.copyRepo.GetById(ctx, id)
+	if err != nil {
+		return domain.Copy{}, err
+	}
+	if existing.BookID != bookID {
+		return domain.Copy{}, fmt.Errorf("copy %d of book %d: %w", id, bookID, domain.ErrNotFound)
+	}
+	return existing, nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/mocks"
+)
+
+func TestAdd(t *testing.T) {
+	mockRepo := new(mocks.CopyRepository)
+	mockRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.Copy")).Return(nil).Once()
+
+	bookCopy := domain.Copy{BookID: 1, Barcode: " 0001 ", BranchID: 1}
+	err := NewCopyUseCase(mockRepo, timeout.Every(time.Second)).Add(context.TODO(), &bookCopy)
+
+	require.NoError(t, err)
+	assert.Equal(t, "0001", bookCopy.Barcode)
+	assert.Equal(t, domain.CopyAvailable, bookCopy.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing domain.CopyStatus
+		status   domain.CopyStatus
+		err      error
+	}{
+		{name: "to repair", existing: domain.CopyAvailable, status: domain.CopyRepair},
+		{name: "lost on loan", existing: domain.CopyOnLoan, status: domain.CopyLost, err: domain.ErrConflict},
+		{name: "loan without checkout", existing: domain.CopyAvailable, status: domain.CopyOnLoan, err: domain.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.CopyRepository)
+			mockRepo.On("GetById", mock.Anything, 5).Return(domain.Copy{ID: 5, BookID: 1, Barcode: "0001", Status: tt.existing}, nil).Once()
+			mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Copy"), tt.existing).Return(nil).Maybe()
+
+			err := NewCopyUseCase(mockRepo, timeout.Every(time.Second)).Update(context.TODO(), &domain.Copy{ID: 5, BookID: 1, Barcode: "0001", BranchID: 1, Status: tt.status})
+
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestDeleteOfOtherBook(t *testing.T) {
+	mockRepo := new(mocks.CopyRepository)
+	mockRepo.On("GetById", mock.Anything, 5).Return(domain.Copy{ID: 5, BookID: 2}, nil).Once()
+
+	err := NewCopyUseCase(mockRepo, timeout.Every(time.Second)).Delete(context.TODO(), 1, 5)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// APIKey is a long-lived credential of a service-to-service client. Only the
+// hash of the secret is stored, the secret itself is shown once on creation
+// and rotation.
+type APIKey struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name" validate:"required"`
+	Owner      string     `json:"owner"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes" validate:"required,dive,permission"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Hash       string     `json:"-"`
+}
+
+// APIKeyUseCase represent the api key's use case contract
+type APIKeyUseCase interface {
+	Fetch(ctx context.Context) ([]APIKey, error)
+	Create(ctx context.Context, key *APIKey) (string, error)
+	Rotate(ctx context.Context, id int) (APIKey, string, error)
+	Revoke(ctx context.Context, id int) error
+	Authenticate(ctx context.Context, secret string) (Principal, error)
+}
+
+// APIKeyRepository represent the api key's repository contract
+type APIKeyRepository interface {
+	Fetch(ctx context.Context) ([]APIKey, error)
+	GetById(ctx context.Context, id int) (APIKey, error)
+	GetByHash(ctx context.Context, hash string) (APIKey, error)
+	Add(ctx context.Context, key *APIKey) error
+	UpdateSecret(ctx context.Context, key *APIKey) error
+	Revoke(ctx context.Context, id int, at time.Time) error
+	Touch(ctx context.Context, id int, at time.Time) error
+}
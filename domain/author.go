@@ -2,17 +2,21 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
 type Author struct {
-	ID        int    `json:"id"`
-	Name      string `json:"name"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 //go:generate mockgen -source=$GOFILE -destination=../mocks/mock_repository/mock_$GOFILE
 // AuthorRepository represent the author's repository contract
 type AuthorRepository interface {
 	GetById(ctx context.Context, id int) (Author, error)
+	// FetchByIDs returns the authors of ids by id, loaded at once. Ids without
+	// an author are missing from the result.
+	FetchByIDs(ctx context.Context, ids []int) (map[int]Author, error)
 }
@@ -2,24 +2,40 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
+// Book is a title of the catalogue. CreatedAt and UpdatedAt are managed by the
+// repository, the values sent by clients are ignored. Repositories load the
+// ID of the Author only.
+//
 //go:generate mockgen -source=$GOFILE -destination=../mocks/mock_repository/mock_$GOFILE
 type Book struct {
-	ID        int    `json:"id"`
-	Title     string `json:"title" validate:"required"`
-	Content   string `json:"content" validate:"required"`
-	Author    Author `json:"author"`
-	UpdatedAt string `json:"updated_at"`
-	CreatedAt string `json:"created_at"`
+	ID           int           `json:"id"`
+	Title        string        `json:"title" validate:"required"`
+	Content      string        `json:"content" validate:"required"`
+	Author       Author        `json:"author"`
+	Version      int           `json:"version"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	Availability *Availability `json:"availability,omitempty"`
 }
 
+// PatchType is the media type of a patch document
+type PatchType string
+
+const (
+	MergePatch PatchType = "application/merge-patch+json"
+	JSONPatch  PatchType = "application/json-patch+json"
+)
+
 // BookUseCase represent the book's use case contract
 type BookUseCase interface {
 	Fetch(ctx context.Context, num int, offset int) ([]Book, error)
 	Add(ctx context.Context, book *Book) error
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, version int) error
 	Update(ctx context.Context, book *Book) error
+	Patch(ctx context.Context, id int, version int, patchType PatchType, patch []byte) (Book, error)
 	GetById(ctx context.Context, id int) (Book, error)
 }
 
@@ -27,7 +43,7 @@ type BookUseCase interface {
 type BookRepository interface {
 	Fetch(ctx context.Context, num int, offset int) ([]Book, error)
 	Add(ctx context.Context, book *Book) error
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int, version int) error
 	Update(ctx context.Context, book *Book) error
 	GetById(ctx context.Context, id int) (Book, error)
 }
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Branch is a location of the library. Copies and members belong to a home
+// branch, holds are picked up at a branch.
+type Branch struct {
+	ID        int       `json:"id"`
+	Code      string    `json:"code" validate:"required,max=16"`
+	Name      string    `json:"name" validate:"required,max=200"`
+	Address   string    `json:"address" validate:"max=500"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BranchUseCase represent the branch's use case contract
+type BranchUseCase interface {
+	Fetch(ctx context.Context) ([]Branch, error)
+	GetById(ctx context.Context, id int) (Branch, error)
+	Add(ctx context.Context, branch *Branch) error
+	Update(ctx context.Context, branch *Branch) error
+	Delete(ctx context.Context, id int) error
+}
+
+// BranchRepository represent the branch's repository contract
+type BranchRepository interface {
+	Fetch(ctx context.Context) ([]Branch, error)
+	GetById(ctx context.Context, id int) (Branch, error)
+	Add(ctx context.Context, branch *Branch) error
+	Update(ctx context.Context, branch *Branch) error
+	Delete(ctx context.Context, id int) error
+}
+
+type branchKey struct{}
+
+// NewContextWithBranch returns a copy of ctx limiting the copies counted by
+// book availability to the branch.
+func NewContextWithBranch(ctx context.Context, branchID int) context.Context {
+	return context.WithValue(ctx, branchKey{}, branchID)
+}
+
+// BranchFromContext returns the branch carried by ctx, if any.
+func BranchFromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(branchKey{}).(int)
+	return id, ok
+}
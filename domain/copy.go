@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// CopyStatus tells whether a copy can be loaned
+type CopyStatus string
+
+// Circulating tells whether the status is managed by loans and holds rather
+// than set by hand.
+func (s CopyStatus) Circulating() bool {
+	return s == CopyOnLoan || s == CopyOnHold || s == CopyInTransit
+}
+
+const (
+	CopyAvailable CopyStatus = "available"
+	CopyOnLoan    CopyStatus = "on_loan"
+	CopyOnHold    CopyStatus = "on_hold"
+	CopyLost      CopyStatus = "lost"
+	CopyRepair    CopyStatus = "repair"
+	CopyInTransit CopyStatus = "in_transit"
+)
+
+// Copy is a physical copy of a Book that can be loaned to a member
+type Copy struct {
+	ID            int        `json:"id"`
+	BookID        int        `json:"book_id"`
+	Barcode       string     `json:"barcode" validate:"required,max=64"`
+	Condition     string     `json:"condition" validate:"omitempty,oneof=new good fair poor damaged"`
+	ShelfLocation string     `json:"shelf_location" validate:"max=64"`
+	BranchID      int        `json:"branch_id" validate:"required"`
+	Status        CopyStatus `json:"status" validate:"omitempty,oneof=available on_loan on_hold lost repair in_transit"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Availability counts the copies of a book
+type Availability struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+}
+
+// CopyUseCase represent the copy's use case contract
+type CopyUseCase interface {
+	FetchByBook(ctx context.Context, bookID int) ([]Copy, error)
+	GetById(ctx context.Context, id int) (Copy, error)
+	Add(ctx context.Context, c *Copy) error
+	Update(ctx context.Context, c *Copy) error
+	Delete(ctx context.Context, bookID int, id int) error
+}
+
+// CopyRepository represent the copy's repository contract
+type CopyRepository interface {
+	FetchByBook(ctx context.Context, bookID int) ([]Copy, error)
+	GetById(ctx context.Context, id int) (Copy, error)
+	Add(ctx context.Context, c *Copy) error
+	// Update stores c if the stored copy still has the status, and fails with
+	// ErrConflict when circulation changed it meanwhile.
+	Update(ctx context.Context, c *Copy, status CopyStatus) error
+	Delete(ctx context.Context, id int) error
+	Availability(ctx context.Context, bookIDs []int, branchID int) (map[int]Availability, error)
+}
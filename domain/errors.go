@@ -1,9 +1,32 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrInternalServerError = errors.New("internal Server Error")
-	ErrNotFound            = errors.New("book was not found")
-	ErrConflict            = errors.New("book already exist")
+	ErrNotFound            = errors.New("requested item was not found")
+	ErrConflict            = errors.New("item already exist")
+	ErrVersionConflict     = errors.New("book was modified by another request")
+	ErrInvalidPatch        = errors.New("patch can not be applied to book")
+	ErrUnauthorized        = errors.New("authentication is required")
+	ErrForbidden           = errors.New("operation is not permitted")
+	ErrNotAvailable        = errors.New("copy is not available")
+	ErrRenewalLimit        = errors.New("loan can not be renewed any more")
+	ErrMembershipInactive  = errors.New("membership is not active")
+	ErrFinesOutstanding    = errors.New("outstanding fines exceed the limit")
+	ErrUnknownTenant       = errors.New("tenant is unknown")
 )
+
+// InvalidIDError reports a path parameter that should hold the id of an entity
+// but is not an integer.
+type InvalidIDError struct {
+	Param string
+	Value string
+}
+
+func (e *InvalidIDError) Error() string {
+	return fmt.Sprintf("%s %q is not an id", e.Param, e.Value)
+}
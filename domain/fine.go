@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Money is an amount in minor currency units
+type Money int64
+
+func (m Money) String() string {
+	sign := ""
+	if m < 0 {
+		sign, m = "-", -m
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, m/100, m%100)
+}
+
+// Fine is charged to a member for returning the copy of a loan late. The fine
+// of a loan grows every day it stays overdue, up to the cap of the policy.
+type Fine struct {
+	ID         int       `json:"id"`
+	MemberID   int       `json:"member_id"`
+	LoanID     int       `json:"loan_id"`
+	Amount     Money     `json:"amount"`
+	AssessedAt time.Time `json:"assessed_at"`
+}
+
+// Payment settles fines of a member
+type Payment struct {
+	ID       int       `json:"id"`
+	MemberID int       `json:"member_id"`
+	Amount   Money     `json:"amount" validate:"gt=0"`
+	Note     string    `json:"note" validate:"max=200"`
+	PaidAt   time.Time `json:"paid_at"`
+}
+
+// Balance sums the fines and payments of a member
+type Balance struct {
+	Fines    Money `json:"fines"`
+	Payments Money `json:"payments"`
+	Due      Money `json:"due"`
+}
+
+// FineUseCase represent the fine's use case contract
+type FineUseCase interface {
+	Assess(ctx context.Context, loan Loan) (Fine, error)
+	// AssessIn assesses the fine of the loan with the repositories of a unit
+	// of work.
+	AssessIn(ctx context.Context, repos Repositories, loan Loan) (Fine, error)
+	FetchByMember(ctx context.Context, memberID int) ([]Fine, error)
+	FetchPayments(ctx context.Context, memberID int) ([]Payment, error)
+	Pay(ctx context.Context, payment *Payment) error
+	Balance(ctx context.Context, memberID int) (Balance, error)
+}
+
+// FineRepository represent the fine's repository contract
+type FineRepository interface {
+	Assess(ctx context.Context, fine *Fine) error
+	FetchByMember(ctx context.Context, memberID int) ([]Fine, error)
+	FetchPayments(ctx context.Context, memberID int) ([]Payment, error)
+	AddPayment(ctx context.Context, payment *Payment) error
+	Balance(ctx context.Context, memberID int) (Balance, error)
+}
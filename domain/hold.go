@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// HoldStatus tells where a hold is in the waitlist of its book
+type HoldStatus string
+
+const (
+	HoldWaiting   HoldStatus = "waiting"
+	HoldInTransit HoldStatus = "in_transit"
+	HoldReady     HoldStatus = "ready"
+	HoldFulfilled HoldStatus = "fulfilled"
+	HoldCancelled HoldStatus = "cancelled"
+	HoldExpired   HoldStatus = "expired"
+)
+
+// Hold is the place of a member in the waitlist of a book. Holds are served in
+// the order they were placed: a returned copy is set aside for the first
+// waiting hold, which is then ready for pickup until ExpiresAt. A copy of
+// another branch is transferred to the pickup branch first.
+type Hold struct {
+	ID             int        `json:"id"`
+	BookID         int        `json:"book_id" validate:"required"`
+	MemberID       int        `json:"member_id" validate:"required"`
+	PickupBranchID int        `json:"pickup_branch_id"`
+	CopyID         *int       `json:"copy_id"`
+	Status         HoldStatus `json:"status"`
+	Position       int        `json:"position,omitempty"`
+	PlacedAt       time.Time  `json:"placed_at"`
+	ReadyAt        *time.Time `json:"ready_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+// HoldUseCase represent the hold's use case contract
+type HoldUseCase interface {
+	Place(ctx context.Context, hold *Hold) error
+	Cancel(ctx context.Context, id int) (Hold, error)
+	GetById(ctx context.Context, id int) (Hold, error)
+	FetchByBook(ctx context.Context, bookID int) ([]Hold, error)
+	FetchByMember(ctx context.Context, memberID int) ([]Hold, error)
+	Assign(ctx context.Context, copyID int) error
+	// AssignIn assigns the copy with the repositories of a unit of work, the
+	// member is told once it is committed.
+	AssignIn(ctx context.Context, repos Repositories, copyID int) error
+	Expire(ctx context.Context) (int, error)
+}
+
+// HoldRepository represent the hold's repository contract
+type HoldRepository interface {
+	Add(ctx context.Context, hold *Hold) error
+	GetById(ctx context.Context, id int) (Hold, error)
+	FetchByBook(ctx context.Context, bookID int) ([]Hold, error)
+	FetchByMember(ctx context.Context, memberID int) ([]Hold, error)
+	Cancel(ctx context.Context, id int) (Hold, error)
+	AssignNext(ctx context.Context, copyID int, readyAt time.Time, expiresAt time.Time) (Hold, *Transfer, error)
+	Expire(ctx context.Context, now time.Time) ([]Hold, error)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Loan is a copy checked out to a member. A copy is on at most one loan that
+// is not returned yet.
+type Loan struct {
+	ID         int        `json:"id"`
+	CopyID     int        `json:"copy_id" validate:"required"`
+	BookID     int        `json:"book_id"`
+	MemberID   int        `json:"member_id" validate:"required"`
+	LoanedAt   time.Time  `json:"loaned_at"`
+	DueAt      time.Time  `json:"due_at"`
+	ReturnedAt *time.Time `json:"returned_at"`
+	Renewals   int        `json:"renewals"`
+	RemindedAt *time.Time `json:"reminded_at"`
+	NoticedAt  *time.Time `json:"noticed_at"`
+}
+
+// Active tells whether the copy of the loan is not returned yet
+func (l Loan) Active() bool {
+	return l.ReturnedAt == nil
+}
+
+// LoanUseCase represent the loan's use case contract
+type LoanUseCase interface {
+	Checkout(ctx context.Context, loan *Loan) error
+	Return(ctx context.Context, id int) (Loan, error)
+	Renew(ctx context.Context, id int) (Loan, error)
+	GetById(ctx context.Context, id int) (Loan, error)
+	FetchByMember(ctx context.Context, memberID int, num int, offset int) ([]Loan, error)
+	FetchByBook(ctx context.Context, bookID int, num int, offset int) ([]Loan, error)
+}
+
+// LoanRepository represent the loan's repository contract
+type LoanRepository interface {
+	Add(ctx context.Context, loan *Loan) error
+	Return(ctx context.Context, loan *Loan) error
+	Renew(ctx context.Context, loan *Loan) error
+	GetById(ctx context.Context, id int) (Loan, error)
+	FetchByMember(ctx context.Context, memberID int, num int, offset int) ([]Loan, error)
+	FetchByBook(ctx context.Context, bookID int, num int, offset int) ([]Loan, error)
+	FetchDueBefore(ctx context.Context, before time.Time) ([]Loan, error)
+	MarkReminded(ctx context.Context, id int, at time.Time) error
+	MarkNoticed(ctx context.Context, id int, at time.Time) error
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MemberStatus tells whether a member may use the library
+type MemberStatus string
+
+const (
+	MemberActive    MemberStatus = "active"
+	MemberSuspended MemberStatus = "suspended"
+	MemberExpired   MemberStatus = "expired"
+)
+
+// Member is a person holding a library card
+type Member struct {
+	ID         int          `json:"id"`
+	Name       string       `json:"name" validate:"required,max=200"`
+	Email      string       `json:"email" validate:"required,email,max=254"`
+	CardNumber string       `json:"card_number" validate:"required,max=32"`
+	BranchID   int          `json:"branch_id" validate:"required"`
+	Status     MemberStatus `json:"status" validate:"omitempty,oneof=active suspended expired"`
+	ExpiresAt  *time.Time   `json:"expires_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// MemberUseCase represent the member's use case contract
+type MemberUseCase interface {
+	Fetch(ctx context.Context, num int, offset int) ([]Member, error)
+	GetById(ctx context.Context, id int) (Member, error)
+	GetByCardNumber(ctx context.Context, cardNumber string) (Member, error)
+	Add(ctx context.Context, member *Member) error
+	Update(ctx context.Context, member *Member) error
+	Delete(ctx context.Context, id int) error
+}
+
+// MemberRepository represent the member's repository contract
+type MemberRepository interface {
+	Fetch(ctx context.Context, num int, offset int) ([]Member, error)
+	GetById(ctx context.Context, id int) (Member, error)
+	GetByCardNumber(ctx context.Context, cardNumber string) (Member, error)
+	GetByEmail(ctx context.Context, email string) (Member, error)
+	Add(ctx context.Context, member *Member) error
+	Update(ctx context.Context, member *Member) error
+	Delete(ctx context.Context, id int) error
+}
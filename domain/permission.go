@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+)
+
+// Permission names an operation a principal may be allowed to perform
+type Permission string
+
+const (
+	PermissionReadBooks      Permission = "books:read"
+	PermissionWriteBooks     Permission = "books:write"
+	PermissionDeleteBooks    Permission = "books:delete"
+	PermissionManageKeys     Permission = "apikeys:manage"
+	PermissionReadMembers    Permission = "members:read"
+	PermissionWriteMembers   Permission = "members:write"
+	PermissionReadLoans      Permission = "loans:read"
+	PermissionWriteLoans     Permission = "loans:write"
+	PermissionReadHolds      Permission = "holds:read"
+	PermissionWriteHolds     Permission = "holds:write"
+	PermissionReadFines      Permission = "fines:read"
+	PermissionWriteFines     Permission = "fines:write"
+	PermissionWriteBranches  Permission = "branches:write"
+	PermissionReadTransfers  Permission = "transfers:read"
+	PermissionWriteTransfers Permission = "transfers:write"
+)
+
+// Permissions lists every known permission
+var Permissions = []Permission{
+	PermissionReadBooks,
+	PermissionWriteBooks,
+	PermissionDeleteBooks,
+	PermissionManageKeys,
+	PermissionReadMembers,
+	PermissionWriteMembers,
+	PermissionReadLoans,
+	PermissionWriteLoans,
+	PermissionReadHolds,
+	PermissionWriteHolds,
+	PermissionReadFines,
+	PermissionWriteFines,
+	PermissionWriteBranches,
+	PermissionReadTransfers,
+	PermissionWriteTransfers,
+}
+
+// Authorizer represent the contract deciding whether the principal of a
+// context may perform an operation
+type Authorizer interface {
+	Authorize(ctx context.Context, permission Permission) error
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+)
+
+type primaryKey struct{}
+
+// NewContextWithPrimary returns a copy of ctx whose reads go to the primary
+// database rather than to a replica, so that they see every write committed
+// before, and skip the caches.
+func NewContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// PrimaryFromContext reports whether the reads of ctx must go to the primary.
+func PrimaryFromContext(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
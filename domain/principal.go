@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+)
+
+// Principal is the authenticated caller of the service. Users are granted
+// permissions through their roles, api keys hold their permissions as scopes.
+// Tenant is the library the caller belongs to, empty when it is not bound to
+// one.
+type Principal struct {
+	Subject string   `json:"subject"`
+	Name    string   `json:"name"`
+	Roles   []string `json:"roles"`
+	Scopes  []string `json:"scopes"`
+	Tenant  string   `json:"tenant,omitempty"`
+}
+
+type principalKey struct{}
+
+// NewContextWithPrincipal returns a copy of ctx carrying the principal.
+func NewContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal carried by ctx, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
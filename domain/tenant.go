@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+)
+
+type tenantKey struct{}
+
+// NewContextWithTenant returns a copy of ctx carrying the tenant, the library
+// every query and event of the request is scoped to.
+func NewContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant carried by ctx, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+)
+
+// Repositories are the repositories a unit of work runs its function with,
+// bound to its transaction.
+type Repositories struct {
+	Books   BookRepository
+	Authors AuthorRepository
+	Loans   LoanRepository
+	Fines   FineRepository
+	Holds   HoldRepository
+	// AfterCommit registers fn to run once the unit of work is committed, such
+	// as the notifications about what it stored.
+	AfterCommit func(fn func())
+}
+
+// UnitOfWork runs functions whose reads and writes must be atomic.
+type UnitOfWork interface {
+	// Do runs fn in a single transaction, committed when fn returns nil and
+	// rolled back when it returns an error or panics. fn may be run again when
+	// the transaction conflicts with a concurrent one, so it must not have side
+	// effects besides those on repos.
+	Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
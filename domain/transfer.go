@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TransferStatus tells where a transfer is on its way between branches
+type TransferStatus string
+
+const (
+	TransferRequested TransferStatus = "requested"
+	TransferInTransit TransferStatus = "in_transit"
+	TransferReceived  TransferStatus = "received"
+	TransferCancelled TransferStatus = "cancelled"
+)
+
+// Transfer moves a copy from its branch to another one. The copy is in transit
+// from the request until it is received or the transfer is cancelled, and it
+// belongs to the destination branch once received. Transfers of a hold bring
+// the copy to the pickup branch of the hold.
+type Transfer struct {
+	ID           int            `json:"id"`
+	CopyID       int            `json:"copy_id" validate:"required"`
+	FromBranchID int            `json:"from_branch_id"`
+	ToBranchID   int            `json:"to_branch_id" validate:"required"`
+	HoldID       *int           `json:"hold_id"`
+	Status       TransferStatus `json:"status"`
+	RequestedAt  time.Time      `json:"requested_at"`
+	ShippedAt    *time.Time     `json:"shipped_at"`
+	ReceivedAt   *time.Time     `json:"received_at"`
+}
+
+// TransferUseCase represent the transfer's use case contract
+type TransferUseCase interface {
+	Request(ctx context.Context, transfer *Transfer) error
+	Ship(ctx context.Context, id int) (Transfer, error)
+	Receive(ctx context.Context, id int) (Transfer, error)
+	Cancel(ctx context.Context, id int) (Transfer, error)
+	GetById(ctx context.Context, id int) (Transfer, error)
+	FetchByBranch(ctx context.Context, branchID int) ([]Transfer, error)
+}
+
+// TransferRepository represent the transfer's repository contract
+type TransferRepository interface {
+	Add(ctx context.Context, transfer *Transfer) error
+	Ship(ctx context.Context, id int, at time.Time) (Transfer, error)
+	Receive(ctx context.Context, id int, at time.Time) (Transfer, error)
+	Cancel(ctx context.Context, id int) (Transfer, error)
+	GetById(ctx context.Context, id int) (Transfer, error)
+	FetchByBranch(ctx context.Context, branchID int) ([]Transfer, error)
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/validation"
+)
+
+const ID = "id"
+
+type FineHandler struct {
+	FUseCase  domain.FineUseCase
+	validator *validator.Validate
+}
+
+// NewFineHandler registers the fine routes on e, each of them wrapped in
+// middleware.
+func NewFineHandler(e *echo.Echo, us domain.FineUseCase, middleware ...echo.MiddlewareFunc) {
+	handler := &FineHandler{FUseCase: us, validator: validation.Validator()}
+	handler.Register(e.Group("/api/v1", middleware...))
+}
+
+func (h *FineHandler) Register(g *echo.Group) {
+	g.GET("/members/:id/fines", h.FetchByMember)
+	g.GET("/members/:id/payments", h.FetchPayments)
+	g.POST("/members/:id/payments", h.Pay)
+	g.GET("/members/:id/balance", h.Balance)
+}
+
+func (h FineHandler) FetchByMember(c echo.Context) error {
+	id, err := memberID(c)
+	if err != nil {
+		return err
+	}
+
+	fines, err := h.FUseCase.FetchByMember(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, fines)
+}
+
+func (h FineHandler) FetchPayments(c echo.Context) error {
+	id, err := memberID(c)
+	if err != nil {
+		return err
+	}
+
+	payments, err := h.FUseCase.FetchPayments(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, payments)
+}
+
+func (h FineHandler) Pay(c echo.Context) error {
+	id, err := memberID(c)
+	if err != nil {
+		return err
+	}
+	var payment domain.Payment
+	if err = c.Bind(&payment); err != nil {
+		return err
+	}
+	payment.MemberID = id
+	if err = h.validator.Struct(&payment); err != nil {
+		return err
+	}
+
+	if err = h.FUseCase.Pay(c.Request().Context(), &payment); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, payment)
+}
+
+func (h FineHandler) Balance(c echo.Context) error {
+	id, err := memberID(c)
+	if err != nil {
+		return err
+	}
+
+	balance, err := h.FUseCase.Balance(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, balance)
+}
+
+func memberID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param(ID))
+	if err != nil {
+		return 0, &domain.InvalidIDError{Param: ID, Value: c.Param(ID)}
+	}
+	return id, nil
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/validation"
+)
+
+func TestPay(t *testing.T) {
+	mockUCase := new(mocks.FineUseCase)
+	mockUCase.On("Pay", mock.Anything, &domain.Payment{MemberID: 3, Amount: 250, Note: "cash"}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Payment).ID = 7 }).
+		Return(nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/members/3/payments", strings.NewReader(`{"amount":250,"note":"cash"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(ID)
+	c.SetParamValues("3")
+	handler := FineHandler{FUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, handler.Pay(c))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":7`)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestPayWithoutAmount(t *testing.T) {
+	mockUCase := new(mocks.FineUseCase)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/members/3/payments", strings.NewReader(`{"note":"cash"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(ID)
+	c.SetParamValues("3")
+	handler := FineHandler{FUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.Pay)(c))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertNotCalled(t, "Pay", mock.Anything, mock.Anything)
+}
+
+func TestBalance(t *testing.T) {
+	mockUCase := new(mocks.FineUseCase)
+	mockUCase.On("Balance", mock.Anything, 3).Return(domain.Balance{Fines: 700, Payments: 250, Due: 450}, nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/api/v1/members/3/balance", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(ID)
+	c.SetParamValues("3")
+	handler := FineHandler{FUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, handler.Balance(c))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"fines":700,"payments":250,"due":450}`, rec.Body.String())
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+)
+
+const foreignKeyViolation = "23503"
+
+type postgresFineRepository struct {
+	Conn *sql.DB
+	tx   *sql.Tx
+}
+
+func NewPostgresFineRepository(Conn *sql.DB) domain.FineRepository {
+	return &postgresFineRepository{Conn: Conn}
+}
+
+// NewPostgresFineRepositoryTx returns the fines of tx, read and written in it
+// by a unit of work.
+func NewPostgresFineRepositoryTx(tx *sql.Tx) domain.FineRepository {
+	return &postgresFineRepository{tx: tx}
+}
+
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// db returns where statements run: the transaction of the repository, if any,
+// or Conn.
+func (p *postgresFineRepository) db() conn {
+	if p.tx != nil {
+		return p.tx
+	}
+	return p.Conn
+}
+
+// Assess stores the fine of its loan. A loan has a single fine, which never
+// decreases when assessed again.
+func (p *postgresFineRepository) Assess(ctx context.Context, fine *domain.Fine) error {
+	return p.db().QueryRowContext(ctx, `INSERT INTO fine (member_id, loan_id, amount, assessed_at) VALUES ($1, $2, $3, $4) `+
+		`ON CONFLICT (loan_id) DO UPDATE SET amount = GREATEST(fine.amount, EXCLUDED.amount), assessed_at = EXCLUDED.assessed_at `+
+		`RETURNING id, amount`,
+		fine.MemberID, fine.LoanID, fine.Amount, fine.AssessedAt).Scan(&fine.ID, &fine.Amount)
+}
+
+func (p *postgresFineRepository) FetchByMember(ctx context.Context, memberID int) ([]domain.Fine, error) {
+	rows, err := p.db().QueryContext(ctx, `SELECT id, member_id, loan_id, amount, assessed_at FROM fine WHERE member_id = $1 ORDER BY id`, memberID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	result := make([]domain.Fine, 0)
+	for rows.Next() {
+		fine := domain.Fine{}
+		if err = rows.Scan(&fine.ID, &fine.MemberID, &fine.LoanID, &fine.Amount, &fine.AssessedAt); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, fine)
+	}
+	return result, rows.Err()
+}
+
+func (p *postgresFineRepository) FetchPayments(ctx context.Context, memberID int) ([]domain.Payment, error) {
+	rows, err := p.db().QueryContext(ctx, `SELECT id, member_id, amount, note, paid_at FROM payment WHERE member_id = $1 ORDER BY id`, memberID)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	result := make([]domain.Payment, 0)
+	for rows.Next() {
+		payment := domain.Payment{}
+		if err = rows.Scan(&payment.ID, &payment.MemberID, &payment.Amount, &payment.Note, &payment.PaidAt); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, payment)
+	}
+	return result, rows.Err()
+}
+
+func (p *postgresFineRepository) AddPayment(ctx context.Context, payment *domain.Payment) error {
+	err := p.db().QueryRowContext(ctx, `INSERT INTO payment (member_id, amount, note, paid_at) VALUES ($1, $2, $3, $4) RETURNING id`,
+		payment.MemberID, payment.Amount, payment.Note, payment.PaidAt).Scan(&payment.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return fmt.Errorf("member %d: %w", payment.MemberID, domain.ErrNotFound)
+	}
+	return err
+}
+
+func (p *postgresFineRepository) Balance(ctx context.Context, memberID int) (domain.Balance, error) {
+	var balance domain.Balance
+	err := p.db().QueryRowContext(ctx, `SELECT (SELECT COALESCE(sum(amount), 0) FROM fine WHERE member_id = $1), `+
+		`(SELECT COALESCE(sum(amount), 0) FROM payment WHERE member_id = $1)`, memberID).Scan(&balance.Fines, &balance.Payments)
+	balance.Due = balance.Fines - balance.Payments
+	return balance, err
+}
+
+func closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		logrus.Error(err)
+	}
+}
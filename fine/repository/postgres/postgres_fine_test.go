@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+)
+
+func TestAssessKeepsLargerAmount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO fine (member_id, loan_id, amount, assessed_at) VALUES ($1, $2, $3, $4) ON CONFLICT (loan_id)`)).
+		WithArgs(3, 9, domain.Money(50), now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "amount"}).AddRow(4, 75))
+
+	fine := domain.Fine{MemberID: 3, LoanID: 9, Amount: 50, AssessedAt: now}
+	err = NewPostgresFineRepository(db).Assess(context.TODO(), &fine)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, fine.ID)
+	assert.Equal(t, domain.Money(75), fine.Amount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT (SELECT COALESCE(sum(amount), 0) FROM fine WHERE member_id = $1)`)).WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"fines", "payments"}).AddRow(700, 250))
+
+	balance, err := NewPostgresFineRepository(db).Balance(context.TODO(), 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.Balance{Fines: 700, Payments: 250, Due: 450}, balance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
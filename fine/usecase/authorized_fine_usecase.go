@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// authorizedFineUseCase checks the permission of the caller before every
+// operation of the wrapped use case.
+type authorizedFineUseCase struct {
+	next       domain.FineUseCase
+	authorizer domain.Authorizer
+}
+
+func NewAuthorizedFineUseCase(next domain.FineUseCase, authorizer domain.Authorizer) domain.FineUseCase {
+	return &authorizedFineUseCase{next: next, authorizer: authorizer}
+}
+
+func (a *authorizedFineUseCase) Assess(ctx context.Context, loan domain.Loan) (domain.Fine, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteFines); err != nil {
+		return domain.Fine{}, err
+	}
+	return a.next.Assess(ctx, loan)
+}
+
+func (a *authorizedFineUseCase) AssessIn(ctx context.Context, repos domain.Repositories, loan domain.Loan) (domain.Fine, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteFines); err != nil {
+		return domain.Fine{}, err
+	}
+	return a.next.AssessIn(ctx, repos, loan)
+}
+
+func (a *authorizedFineUseCase) FetchByMember(ctx context.Context, memberID int) ([]domain.Fine, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadFines); err != nil {
+		return nil, err
+	}
+	return a.next.FetchByMember(ctx, memberID)
+}
+
+func (a *authorizedFineUseCase) FetchPayments(ctx context.Context, memberID int) ([]domain.Payment, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadFines); err != nil {
+		return nil, err
+	}
+	return a.next.FetchPayments(ctx, memberID)
+}
+
+func (a *authorizedFineUseCase) Pay(ctx context.Context, payment *domain.Payment) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteFines); err != nil {
+		return err
+	}
+	return a.next.Pay(ctx, payment)
+}
+
+func (a *authorizedFineUseCase) Balance(ctx context.Context, memberID int) (domain.Balance, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadFines); err != nil {
+		return domain.Balance{}, err
+	}
+	return a.next.Balance(ctx, memberID)
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/validation"
+)
+
+// Policy holds the rules fines are calculated with
+type Policy struct {
+	// PerDay is charged for every started day a loan is overdue.
+	PerDay domain.Money
+	// Cap limits the fine of a single loan, zero means no limit.
+	Cap domain.Money
+	// Grace is the time after the due date that is not charged.
+	Grace time.Duration
+}
+
+// Amount returns the fine of a loan due at due and returned, or still open,
+// at asOf.
+func (p Policy) Amount(due time.Time, asOf time.Time) domain.Money {
+	overdue := asOf.Sub(due) - p.Grace
+	if overdue <= 0 {
+		return 0
+	}
+	days := int64((overdue + 24*time.Hour - 1) / (24 * time.Hour))
+	amount := domain.Money(days) * p.PerDay
+	if p.Cap > 0 && amount > p.Cap {
+		amount = p.Cap
+	}
+	return amount
+}
+
+type fineUseCase struct {
+	fineRepo  domain.FineRepository
+	policy    Policy
+	timeouts  timeout.Timeouts
+	validator *validator.Validate
+	now       func() time.Time
+}
+
+func NewFineUseCase(f domain.FineRepository, policy Policy, timeouts timeout.Timeouts) domain.FineUseCase {
+	return &fineUseCase{
+		fineRepo:  f,
+		policy:    policy,
+		timeouts:  timeouts,
+		validator: validation.Validator(),
+		now:       time.Now,
+	}
+}
+
+// Assess charges the fine of the loan as of its return, or as of now while it
+// is still open. Loans that are not overdue are not charged.
+func (f *fineUseCase) Assess(c context.Context, loan domain.Loan) (domain.Fine, error) {
+	return f.assess(c, f.fineRepo, loan)
+}
+
+func (f *fineUseCase) AssessIn(c context.Context, repos domain.Repositories, loan domain.Loan) (domain.Fine, error) {
+	return f.assess(c, repos.Fines, loan)
+}
+
+func (f *fineUseCase) assess(c context.Context, fines domain.FineRepository, loan domain.Loan) (domain.Fine, error) {
+	asOf := f.now().UTC()
+	if loan.ReturnedAt != nil {
+		asOf = *loan.ReturnedAt
+	}
+	fine := domain.Fine{MemberID: loan.MemberID, LoanID: loan.ID, Amount: f.policy.Amount(loan.DueAt, asOf), AssessedAt: asOf}
+	if fine.Amount == 0 {
+		return fine, nil
+	}
+	ctxt, cancel := context.WithTimeout(c, f.timeouts.Of("assess"))
+	defer cancel()
+
+	err := fines.Assess(ctxt, &fine)
+	return fine, err
+}
+
+func (f *fineUseCase) FetchByMember(c context.Context, memberID int) ([]domain.Fine, error) {
+	ctxt, cancel := context.WithTimeout(c, f.timeouts.Of("fetch_by_member"))
+	defer cancel()
+
+	return f.fineRepo.FetchByMember(ctxt, memberID)
+}
+
+func (f *fineUseCase) FetchPayments(c context.Context, memberID int) ([]domain.Payment, error) {
+	ctxt, cancel := context.WithTimeout(c, f.timeouts.Of("fetch_payments"))
+	defer cancel()
+
+	return f.fineRepo.FetchPayments(ctxt, memberID)
+}
+
+func (f *fineUseCase) Pay(c context.Context, payment *domain.Payment) error {
+	if err := f.validator.Struct(payment); err != nil {
+		return err
+	}
+	ctxt, cancel := context.WithTimeout(c, f.timeouts.Of("pay"))
+	defer cancel()
+
+	payment.ID = 0
+	payment.PaidAt = f.now().UTC()
+	return f.fineRepo.AddPayment(ctxt, payment)
+}
+
+func (f *fineUseCase) Balance(c context.Context, memberID int) (domain.Balance, error) {
+	ctxt, cancel := context.WithTimeout(c, f.timeouts.Of("balance"))
+	defer cancel()
+
+	return f.fineRepo.Balance(ctxt, memberID)
+}
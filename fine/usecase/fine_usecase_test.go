@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/mocks"
+)
+
+var testPolicy = Policy{PerDay: 25, Cap: 100, Grace: 24 * time.Hour}
+
+func TestAmount(t *testing.T) {
+	due := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		asOf time.Time
+		want domain.Money
+	}{
+		"before due":        {asOf: due.Add(-time.Hour), want: 0},
+		"within grace":      {asOf: due.Add(24 * time.Hour), want: 0},
+		"first started day": {asOf: due.Add(25 * time.Hour), want: 25},
+		"two full days":     {asOf: due.Add(72 * time.Hour), want: 50},
+		"capped":            {asOf: due.Add(30 * 24 * time.Hour), want: 100},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, testPolicy.Amount(due, tt.asOf))
+		})
+	}
+}
+
+func TestAssessReturnedLoan(t *testing.T) {
+	due := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	returned := due.Add(50 * time.Hour)
+	mockFineRepo := new(mocks.FineRepository)
+	mockFineRepo.On("Assess", mock.Anything, &domain.Fine{MemberID: 3, LoanID: 9, Amount: 50, AssessedAt: returned}).Return(nil).Once()
+
+	u := NewFineUseCase(mockFineRepo, testPolicy, timeout.Every(time.Second)).(*fineUseCase)
+	u.now = func() time.Time { return returned.Add(240 * time.Hour) }
+	fine, err := u.Assess(context.TODO(), domain.Loan{ID: 9, MemberID: 3, DueAt: due, ReturnedAt: &returned})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.Money(50), fine.Amount)
+	mockFineRepo.AssertExpectations(t)
+}
+
+func TestAssessNotOverdue(t *testing.T) {
+	mockFineRepo := new(mocks.FineRepository)
+
+	fine, err := NewFineUseCase(mockFineRepo, testPolicy, timeout.Every(time.Second)).
+		Assess(context.TODO(), domain.Loan{ID: 9, MemberID: 3, DueAt: time.Now().Add(time.Hour)})
+
+	require.NoError(t, err)
+	assert.Zero(t, fine.Amount)
+	mockFineRepo.AssertNotCalled(t, "Assess", mock.Anything, mock.Anything)
+}
+
+func TestPayInvalidAmount(t *testing.T) {
+	mockFineRepo := new(mocks.FineRepository)
+
+	err := NewFineUseCase(mockFineRepo, testPolicy, timeout.Every(time.Second)).Pay(context.TODO(), &domain.Payment{MemberID: 3, Amount: -5})
+
+	assert.Error(t, err)
+	mockFineRepo.AssertNotCalled(t, "AddPayment", mock.Anything, mock.Anything)
+}
@@ -1,11 +1,12 @@
 module github.com/bxcodec/library
 
-go 1.12
+go 1.16
 
 require (
 	github.com/bxcodec/faker v1.4.2
-	github.com/go-playground/locales v0.12.1 // indirect
-	github.com/go-playground/universal-translator v0.16.0 // indirect
+	github.com/evanphx/json-patch v4.12.0+incompatible
+	github.com/go-playground/locales v0.12.1
+	github.com/go-playground/universal-translator v0.16.0
 	github.com/labstack/echo v3.3.5+incompatible
 	github.com/labstack/gommon v0.0.0-20180426014445-588f4e8bddc6 // indirect
 	github.com/lib/pq v1.10.4
@@ -16,7 +17,10 @@ require (
 	github.com/stretchr/testify v1.7.1
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
 	github.com/valyala/fasttemplate v0.0.0-20170224212429-dcecefd839c4 // indirect
+	golang.org/x/sync v0.1.0
+	golang.org/x/text v0.3.7
 	gopkg.in/DATA-DOG/go-sqlmock.v1 v1.3.0
 	gopkg.in/go-playground/assert.v1 v1.2.1 // indirect
 	gopkg.in/go-playground/validator.v9 v9.15.0
+	modernc.org/sqlite v1.14.6
 )
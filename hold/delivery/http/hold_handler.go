@@ -0,0 +1,110 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/validation"
+)
+
+const ID = "id"
+
+type HoldHandler struct {
+	HUseCase  domain.HoldUseCase
+	validator *validator.Validate
+}
+
+// NewHoldHandler registers the hold routes on e, each of them wrapped in
+// middleware.
+func NewHoldHandler(e *echo.Echo, us domain.HoldUseCase, middleware ...echo.MiddlewareFunc) {
+	handler := &HoldHandler{HUseCase: us, validator: validation.Validator()}
+	handler.Register(e.Group("/api/v1", middleware...))
+}
+
+func (h *HoldHandler) Register(g *echo.Group) {
+	g.POST("/holds", h.Place)
+	g.GET("/holds/:id", h.GetById)
+	g.POST("/holds/:id/cancel", h.Cancel)
+	g.GET("/books/:id/holds", h.FetchByBook)
+	g.GET("/members/:id/holds", h.FetchByMember)
+}
+
+func (h HoldHandler) Place(c echo.Context) error {
+	var hold domain.Hold
+	if err := c.Bind(&hold); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(&hold); err != nil {
+		return err
+	}
+
+	if err := h.HUseCase.Place(c.Request().Context(), &hold); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, hold)
+}
+
+func (h HoldHandler) GetById(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	hold, err := h.HUseCase.GetById(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, hold)
+}
+
+func (h HoldHandler) Cancel(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	hold, err := h.HUseCase.Cancel(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, hold)
+}
+
+// FetchByBook returns the waitlist of the book in the order it is served.
+func (h HoldHandler) FetchByBook(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	holds, err := h.HUseCase.FetchByBook(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, holds)
+}
+
+func (h HoldHandler) FetchByMember(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	holds, err := h.HUseCase.FetchByMember(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, holds)
+}
+
+func pathID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param(ID))
+	if err != nil {
+		return 0, &domain.InvalidIDError{Param: ID, Value: c.Param(ID)}
+	}
+	return id, nil
+}
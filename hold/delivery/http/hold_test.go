@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/validation"
+)
+
+func TestPlace(t *testing.T) {
+	mockUCase := new(mocks.HoldUseCase)
+	mockUCase.On("Place", mock.Anything, &domain.Hold{BookID: 1, MemberID: 3}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Hold).Position = 1 }).
+		Return(nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/holds", strings.NewReader(`{"book_id":1,"member_id":3}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := HoldHandler{HUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, handler.Place(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"position":1`)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestPlaceTwice(t *testing.T) {
+	mockUCase := new(mocks.HoldUseCase)
+	mockUCase.On("Place", mock.Anything, mock.AnythingOfType("*domain.Hold")).Return(domain.ErrConflict).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/holds", strings.NewReader(`{"book_id":1,"member_id":3}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := HoldHandler{HUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.Place)(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
@@ -0,0 +1,293 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+)
+
+const (
+	// selectHold computes the position of every waiting hold in the queue of
+	// its book.
+	selectHold = `SELECT h.id, h.book_id, h.member_id, h.pickup_branch_id, h.copy_id, h.status, h.placed_at, h.ready_at, h.expires_at, ` +
+		`CASE WHEN h.status = 'waiting' THEN (SELECT count(*) FROM hold w WHERE w.book_id = h.book_id AND w.status = 'waiting' ` +
+		`AND (w.placed_at, w.id) <= (h.placed_at, h.id)) ELSE 0 END FROM hold h`
+	returningHold = ` RETURNING id, book_id, member_id, pickup_branch_id, copy_id, status, placed_at, ready_at, expires_at, 0`
+
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+type postgresHoldRepository struct {
+	Conn *sql.DB
+	tx   *sql.Tx
+}
+
+func NewPostgresHoldRepository(Conn *sql.DB) domain.HoldRepository {
+	return &postgresHoldRepository{Conn: Conn}
+}
+
+// NewPostgresHoldRepositoryTx returns the holds of tx, read and written in it
+// by a unit of work.
+func NewPostgresHoldRepositoryTx(tx *sql.Tx) domain.HoldRepository {
+	return &postgresHoldRepository{tx: tx}
+}
+
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// db returns where statements run: the transaction of the repository, if any,
+// or Conn.
+func (p *postgresHoldRepository) db() conn {
+	if p.tx != nil {
+		return p.tx
+	}
+	return p.Conn
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func fetch(ctx context.Context, q queryer, query string, args ...interface{}) ([]domain.Hold, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make([]domain.Hold, 0)
+	for rows.Next() {
+		hold := domain.Hold{}
+		err = rows.Scan(
+			&hold.ID,
+			&hold.BookID,
+			&hold.MemberID,
+			&hold.PickupBranchID,
+			&hold.CopyID,
+			&hold.Status,
+			&hold.PlacedAt,
+			&hold.ReadyAt,
+			&hold.ExpiresAt,
+			&hold.Position,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, hold)
+	}
+	return result, rows.Err()
+}
+
+func getOne(ctx context.Context, q queryer, id int, query string, args ...interface{}) (domain.Hold, error) {
+	list, err := fetch(ctx, q, query, args...)
+	if err != nil {
+		return domain.Hold{}, err
+	}
+	if len(list) == 0 {
+		return domain.Hold{}, fmt.Errorf("hold %d: %w", id, domain.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+func (p *postgresHoldRepository) GetById(ctx context.Context, id int) (domain.Hold, error) {
+	return getOne(ctx, p.db(), id, selectHold+` WHERE h.id = $1`, id)
+}
+
+// FetchByBook returns the queue of the book: the holds a copy is set aside for
+// followed by the waiting ones in the order they are served.
+func (p *postgresHoldRepository) FetchByBook(ctx context.Context, bookID int) ([]domain.Hold, error) {
+	return fetch(ctx, p.db(), selectHold+` WHERE h.book_id = $1 AND h.status IN ('waiting', 'in_transit', 'ready') `+
+		`ORDER BY h.status = 'waiting', h.placed_at, h.id`, bookID)
+}
+
+func (p *postgresHoldRepository) FetchByMember(ctx context.Context, memberID int) ([]domain.Hold, error) {
+	return fetch(ctx, p.db(), selectHold+` WHERE h.member_id = $1 ORDER BY h.placed_at DESC, h.id DESC`, memberID)
+}
+
+func (p *postgresHoldRepository) Add(ctx context.Context, hold *domain.Hold) error {
+	err := p.db().QueryRowContext(ctx, `INSERT INTO hold (book_id, member_id, pickup_branch_id, status, placed_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		hold.BookID, hold.MemberID, hold.PickupBranchID, hold.Status, hold.PlacedAt).Scan(&hold.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return fmt.Errorf("member %d already holds book %d: %w", hold.MemberID, hold.BookID, domain.ErrConflict)
+		case foreignKeyViolation:
+			return fmt.Errorf("hold %s: %w", pqErr.Constraint, domain.ErrNotFound)
+		}
+	}
+	return err
+}
+
+// Cancel cancels an active hold and releases the copy set aside for it. A copy
+// in transit completes its transfer and is released on arrival.
+func (p *postgresHoldRepository) Cancel(ctx context.Context, id int) (hold domain.Hold, err error) {
+	tx, err := p.begin(ctx)
+	if err != nil {
+		return domain.Hold{}, err
+	}
+	defer rollback(tx, &err)
+
+	hold, err = getOne(ctx, tx, id, `UPDATE hold SET status = 'cancelled' WHERE id = $1 AND status IN ('waiting', 'in_transit', 'ready')`+returningHold, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Hold{}, fmt.Errorf("active hold %d: %w", id, domain.ErrConflict)
+	}
+	if err != nil {
+		return domain.Hold{}, err
+	}
+	if hold.CopyID != nil {
+		if err = release(ctx, tx, []int{*hold.CopyID}); err != nil {
+			return domain.Hold{}, err
+		}
+	}
+	return hold, tx.Commit()
+}
+
+// AssignNext sets the available copy aside for a hold of its book. A hold the
+// copy was transferred for is served first, otherwise the first waiting hold
+// takes the copy: it is ready when the copy is at its pickup branch, else the
+// copy is sent there by the returned transfer. It fails with domain.ErrNotAvailable when
+// the copy is not available and with domain.ErrNotFound when nobody is waiting.
+func (p *postgresHoldRepository) AssignNext(ctx context.Context, copyID int, readyAt time.Time, expiresAt time.Time) (hold domain.Hold, transfer *domain.Transfer, err error) {
+	tx, err := p.begin(ctx)
+	if err != nil {
+		return domain.Hold{}, nil, err
+	}
+	defer rollback(tx, &err)
+
+	var bookID, branchID int
+	var status domain.CopyStatus
+	err = tx.QueryRowContext(ctx, `SELECT book_id, branch_id, status FROM copy WHERE id = $1 FOR UPDATE`, copyID).Scan(&bookID, &branchID, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Hold{}, nil, fmt.Errorf("copy %d: %w", copyID, domain.ErrNotAvailable)
+	}
+	if err != nil {
+		return domain.Hold{}, nil, err
+	}
+	if status != domain.CopyAvailable {
+		return domain.Hold{}, nil, fmt.Errorf("copy %d is %s: %w", copyID, status, domain.ErrNotAvailable)
+	}
+
+	hold, err = getOne(ctx, tx, 0, `UPDATE hold SET status = 'ready', ready_at = $1, expires_at = $2 `+
+		`WHERE id = (SELECT id FROM hold WHERE copy_id = $3 AND status = 'in_transit' AND pickup_branch_id = $4 LIMIT 1 FOR UPDATE)`+
+		returningHold, readyAt, expiresAt, copyID, branchID)
+	if errors.Is(err, domain.ErrNotFound) {
+		hold, err = getOne(ctx, tx, 0, `UPDATE hold SET status = CASE WHEN pickup_branch_id = $1 THEN 'ready' ELSE 'in_transit' END, copy_id = $2, `+
+			`ready_at = CASE WHEN pickup_branch_id = $1 THEN $3::timestamptz END, expires_at = CASE WHEN pickup_branch_id = $1 THEN $4::timestamptz END `+
+			`WHERE id = (SELECT id FROM hold WHERE book_id = $5 AND status = 'waiting' ORDER BY placed_at, id LIMIT 1 FOR UPDATE SKIP LOCKED)`+
+			returningHold, branchID, copyID, readyAt, expiresAt, bookID)
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.Hold{}, nil, fmt.Errorf("no hold waits for book %d: %w", bookID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return domain.Hold{}, nil, err
+	}
+
+	copyStatus := domain.CopyOnHold
+	if hold.Status == domain.HoldInTransit {
+		copyStatus = domain.CopyInTransit
+		holdID := hold.ID
+		transfer = &domain.Transfer{CopyID: copyID, FromBranchID: branchID, ToBranchID: hold.PickupBranchID, HoldID: &holdID,
+			Status: domain.TransferRequested, RequestedAt: readyAt}
+		err = tx.QueryRowContext(ctx, `INSERT INTO transfer (copy_id, from_branch_id, to_branch_id, hold_id, status, requested_at) `+
+			`VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+			transfer.CopyID, transfer.FromBranchID, transfer.ToBranchID, transfer.HoldID, transfer.Status, transfer.RequestedAt).Scan(&transfer.ID)
+		if err != nil {
+			return domain.Hold{}, nil, err
+		}
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE copy SET status = $1, updated_at = now() WHERE id = $2`, copyStatus, copyID); err != nil {
+		return domain.Hold{}, nil, err
+	}
+	return hold, transfer, tx.Commit()
+}
+
+// Expire closes the ready holds not picked up before now and releases their
+// copies.
+func (p *postgresHoldRepository) Expire(ctx context.Context, now time.Time) (holds []domain.Hold, err error) {
+	tx, err := p.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback(tx, &err)
+
+	holds, err = fetch(ctx, tx, `UPDATE hold SET status = 'expired' WHERE status = 'ready' AND expires_at < $1`+returningHold, now)
+	if err != nil {
+		return nil, err
+	}
+	copyIDs := make([]int, 0, len(holds))
+	for _, hold := range holds {
+		if hold.CopyID != nil {
+			copyIDs = append(copyIDs, *hold.CopyID)
+		}
+	}
+	if err = release(ctx, tx, copyIDs); err != nil {
+		return nil, err
+	}
+	return holds, tx.Commit()
+}
+
+// release makes the copies set aside for holds available again.
+func release(ctx context.Context, tx *txn, copyIDs []int) error {
+	if len(copyIDs) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `UPDATE copy SET status = $1, updated_at = now() WHERE id = ANY($2) AND status = $3`,
+		domain.CopyAvailable, pq.Array(copyIDs), domain.CopyOnHold)
+	return err
+}
+
+// txn is the transaction a method runs in: its own one, or the one of the unit
+// of work the repository is bound to, which only the unit of work ends.
+type txn struct {
+	*sql.Tx
+	bound bool
+}
+
+func (p *postgresHoldRepository) begin(ctx context.Context) (*txn, error) {
+	if p.tx != nil {
+		return &txn{Tx: p.tx, bound: true}, nil
+	}
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &txn{Tx: tx}, nil
+}
+
+func (tx *txn) Commit() error {
+	if tx.bound {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+// rollback rolls tx back when the function owning it fails with *err.
+func rollback(tx *txn, err *error) {
+	if *err == nil || tx.bound {
+		return
+	}
+	if errRollback := tx.Rollback(); errRollback != nil {
+		logrus.Error(errRollback)
+	}
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+)
+
+var holdColumns = []string{"id", "book_id", "member_id", "pickup_branch_id", "copy_id", "status", "placed_at", "ready_at", "expires_at", "position"}
+
+func TestAssignNext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT book_id, branch_id, status FROM copy WHERE id = $1 FOR UPDATE`)).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "branch_id", "status"}).AddRow(1, 2, "available"))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE hold SET status = 'ready', ready_at = $1, expires_at = $2 WHERE id = (SELECT id FROM hold WHERE copy_id = $3`)).
+		WithArgs(now, expires, 5, 2).
+		WillReturnRows(sqlmock.NewRows(holdColumns))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE hold SET status = CASE WHEN pickup_branch_id = $1`)).WithArgs(2, 5, now, expires, 1).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(11, 1, 3, 2, 5, "ready", now, now, expires, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE copy SET status = $1`)).WithArgs(domain.CopyOnHold, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	hold, transfer, err := NewPostgresHoldRepository(db).AssignNext(context.TODO(), 5, now, expires)
+
+	require.NoError(t, err)
+	assert.Nil(t, transfer)
+	assert.Equal(t, domain.HoldReady, hold.Status)
+	assert.Equal(t, 5, *hold.CopyID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignNextTransfersToPickupBranch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT book_id, branch_id, status FROM copy`)).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "branch_id", "status"}).AddRow(1, 2, "available"))
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE copy_id = $3 AND status = 'in_transit'`)).WillReturnRows(sqlmock.NewRows(holdColumns))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE hold SET status = CASE WHEN pickup_branch_id = $1`)).WithArgs(2, 5, now, expires, 1).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(11, 1, 3, 1, 5, "in_transit", now, nil, nil, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO transfer`)).WithArgs(5, 2, 1, 11, domain.TransferRequested, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE copy SET status = $1`)).WithArgs(domain.CopyInTransit, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	hold, transfer, err := NewPostgresHoldRepository(db).AssignNext(context.TODO(), 5, now, expires)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.HoldInTransit, hold.Status)
+	require.NotNil(t, transfer)
+	assert.Equal(t, 8, transfer.ID)
+	assert.Equal(t, 1, transfer.ToBranchID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignNextNobodyWaiting(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT book_id, branch_id, status FROM copy`)).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "branch_id", "status"}).AddRow(1, 2, "available"))
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE copy_id = $3 AND status = 'in_transit'`)).WillReturnRows(sqlmock.NewRows(holdColumns))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE hold SET status = CASE`)).WillReturnRows(sqlmock.NewRows(holdColumns))
+	mock.ExpectRollback()
+
+	_, _, err = NewPostgresHoldRepository(db).AssignNext(context.TODO(), 5, time.Now(), time.Now())
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelReleasesCopy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE hold SET status = 'cancelled'`)).WithArgs(11).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(11, 1, 3, 1, 5, "cancelled", now, now, now, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE copy SET status = $1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	hold, err := NewPostgresHoldRepository(db).Cancel(context.TODO(), 11)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.HoldCancelled, hold.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// authorizedHoldUseCase checks the permission of the caller before every
+// operation of the wrapped use case.
+type authorizedHoldUseCase struct {
+	next       domain.HoldUseCase
+	authorizer domain.Authorizer
+}
+
+func NewAuthorizedHoldUseCase(next domain.HoldUseCase, authorizer domain.Authorizer) domain.HoldUseCase {
+	return &authorizedHoldUseCase{next: next, authorizer: authorizer}
+}
+
+func (a *authorizedHoldUseCase) Place(ctx context.Context, hold *domain.Hold) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteHolds); err != nil {
+		return err
+	}
+	return a.next.Place(ctx, hold)
+}
+
+func (a *authorizedHoldUseCase) Cancel(ctx context.Context, id int) (domain.Hold, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteHolds); err != nil {
+		return domain.Hold{}, err
+	}
+	return a.next.Cancel(ctx, id)
+}
+
+func (a *authorizedHoldUseCase) GetById(ctx context.Context, id int) (domain.Hold, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadHolds); err != nil {
+		return domain.Hold{}, err
+	}
+	return a.next.GetById(ctx, id)
+}
+
+func (a *authorizedHoldUseCase) FetchByBook(ctx context.Context, bookID int) ([]domain.Hold, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadHolds); err != nil {
+		return nil, err
+	}
+	return a.next.FetchByBook(ctx, bookID)
+}
+
+func (a *authorizedHoldUseCase) FetchByMember(ctx context.Context, memberID int) ([]domain.Hold, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadHolds); err != nil {
+		return nil, err
+	}
+	return a.next.FetchByMember(ctx, memberID)
+}
+
+func (a *authorizedHoldUseCase) Assign(ctx context.Context, copyID int) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteHolds); err != nil {
+		return err
+	}
+	return a.next.Assign(ctx, copyID)
+}
+
+func (a *authorizedHoldUseCase) AssignIn(ctx context.Context, repos domain.Repositories, copyID int) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteHolds); err != nil {
+		return err
+	}
+	return a.next.AssignIn(ctx, repos, copyID)
+}
+
+func (a *authorizedHoldUseCase) Expire(ctx context.Context) (int, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteHolds); err != nil {
+		return 0, err
+	}
+	return a.next.Expire(ctx)
+}
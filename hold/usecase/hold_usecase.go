@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/i18n"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/mail"
+	mb "github.com/bxcodec/library/message_broker"
+)
+
+type holdUseCase struct {
+	holdRepo      domain.HoldRepository
+	copyRepo      domain.CopyRepository
+	memberRepo    domain.MemberRepository
+	bookRepo      domain.BookRepository
+	mailService   mail.Sender
+	messageBroker mb.MessageBroker
+	pickupWindow  time.Duration
+	timeouts      timeout.Timeouts
+	now           func() time.Time
+}
+
+func NewHoldUseCase(h domain.HoldRepository, c domain.CopyRepository, m domain.MemberRepository, b domain.BookRepository,
+	mail mail.Sender, mb mb.MessageBroker, pickupWindow time.Duration, timeouts timeout.Timeouts) domain.HoldUseCase {
+	return &holdUseCase{
+		holdRepo:      h,
+		copyRepo:      c,
+		memberRepo:    m,
+		bookRepo:      b,
+		mailService:   mail,
+		messageBroker: mb,
+		pickupWindow:  pickupWindow,
+		timeouts:      timeouts,
+		now:           time.Now,
+	}
+}
+
+// Place puts the member at the end of the waitlist of the book, to be picked up
+// at the home branch of the member unless another branch is asked for. A copy
+// that is available already is set aside for the queue right away, the copies
+// of the pickup branch first.
+func (h *holdUseCase) Place(c context.Context, hold *domain.Hold) error {
+	c = domain.NewContextWithPrimary(c)
+	ctxt, cancel := context.WithTimeout(c, h.timeouts.Of("place"))
+	defer cancel()
+
+	now := h.now().UTC()
+	member, err := h.memberRepo.GetById(ctxt, hold.MemberID)
+	if err != nil {
+		return err
+	}
+	if member.Status != domain.MemberActive || (member.ExpiresAt != nil && now.After(*member.ExpiresAt)) {
+		return fmt.Errorf("member %d: %w", member.ID, domain.ErrMembershipInactive)
+	}
+
+	hold.ID = 0
+	if hold.PickupBranchID == 0 {
+		hold.PickupBranchID = member.BranchID
+	}
+	hold.CopyID = nil
+	hold.Status = domain.HoldWaiting
+	hold.PlacedAt = now
+	hold.ReadyAt = nil
+	hold.ExpiresAt = nil
+	if err = h.holdRepo.Add(ctxt, hold); err != nil {
+		return err
+	}
+
+	copies, err := h.copyRepo.FetchByBook(ctxt, hold.BookID)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(copies, func(i, j int) bool {
+		return copies[i].BranchID == hold.PickupBranchID && copies[j].BranchID != hold.PickupBranchID
+	})
+	for _, bookCopy := range copies {
+		if bookCopy.Status == domain.CopyAvailable {
+			if err = h.Assign(c, bookCopy.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	placed, err := h.holdRepo.GetById(ctxt, hold.ID)
+	if err != nil {
+		return err
+	}
+	*hold = placed
+	return nil
+}
+
+// Cancel withdraws the hold, a copy set aside for it passes to the next hold.
+func (h *holdUseCase) Cancel(c context.Context, id int) (domain.Hold, error) {
+	ctxt, cancel := context.WithTimeout(c, h.timeouts.Of("cancel"))
+	defer cancel()
+
+	hold, err := h.holdRepo.Cancel(ctxt, id)
+	if err != nil {
+		return domain.Hold{}, err
+	}
+	if hold.CopyID != nil {
+		if err = h.Assign(c, *hold.CopyID); err != nil {
+			return domain.Hold{}, err
+		}
+	}
+	return hold, nil
+}
+
+func (h *holdUseCase) GetById(c context.Context, id int) (domain.Hold, error) {
+	ctxt, cancel := context.WithTimeout(c, h.timeouts.Of("get_by_id"))
+	defer cancel()
+
+	return h.holdRepo.GetById(ctxt, id)
+}
+
+func (h *holdUseCase) FetchByBook(c context.Context, bookID int) ([]domain.Hold, error) {
+	ctxt, cancel := context.WithTimeout(c, h.timeouts.Of("fetch_by_book"))
+	defer cancel()
+
+	return h.holdRepo.FetchByBook(ctxt, bookID)
+}
+
+func (h *holdUseCase) FetchByMember(c context.Context, memberID int) ([]domain.Hold, error) {
+	ctxt, cancel := context.WithTimeout(c, h.timeouts.Of("fetch_by_member"))
+	defer cancel()
+
+	return h.holdRepo.FetchByMember(ctxt, memberID)
+}
+
+// Assign sets the copy aside for the first waiting hold of its book and tells
+// the member it is ready, or requests the transfer of the copy to the pickup
+// branch of the hold. Nothing happens when the copy is not available or nobody
+// waits for it.
+func (h *holdUseCase) Assign(c context.Context, copyID int) error {
+	return h.assign(c, h.holdRepo, func(fn func()) { fn() }, copyID)
+}
+
+func (h *holdUseCase) AssignIn(c context.Context, repos domain.Repositories, copyID int) error {
+	return h.assign(c, repos.Holds, repos.AfterCommit, copyID)
+}
+
+// assign assigns the copy with holds and leaves telling about it to
+// afterCommit, once the assignment is stored.
+func (h *holdUseCase) assign(c context.Context, holds domain.HoldRepository, afterCommit func(func()), copyID int) error {
+	ctxt, cancel := context.WithTimeout(c, h.timeouts.Of("assign"))
+	defer cancel()
+
+	now := h.now().UTC()
+	hold, transfer, err := holds.AssignNext(ctxt, copyID, now, now.Add(h.pickupWindow))
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrNotAvailable) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if transfer != nil {
+		afterCommit(func() { h.publishTransfer(c, *transfer) })
+		return nil
+	}
+	afterCommit(func() { h.notify(c, hold) })
+	return nil
+}
+
+// Expire closes the holds whose pickup window passed and hands their copies
+// on to the next holds, it returns the number of expired holds.
+func (h *holdUseCase) Expire(c context.Context) (int, error) {
+	ctxt, cancel := context.WithTimeout(c, h.timeouts.Of("expire"))
+	defer cancel()
+
+	expired, err := h.holdRepo.Expire(ctxt, h.now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	for _, hold := range expired {
+		if hold.CopyID == nil {
+			continue
+		}
+		if err = h.Assign(c, *hold.CopyID); err != nil {
+			return len(expired), err
+		}
+	}
+	return len(expired), nil
+}
+
+// notify mails the member of a ready hold. The hold is already stored, so a
+// failure is only logged.
+func (h *holdUseCase) notify(c context.Context, hold domain.Hold) {
+	ctxt, cancel := context.WithTimeout(c, h.timeouts.Of("notify"))
+	defer cancel()
+
+	member, err := h.memberRepo.GetById(ctxt, hold.MemberID)
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+	book, err := h.bookRepo.GetById(ctxt, hold.BookID)
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+
+	locale := i18n.Locale(c)
+	event := mb.Event{
+		Subject: string(mb.HOLD_READY),
+		Content: i18n.T(locale, i18n.HoldReady, book.Title, hold.ExpiresAt.Format("2006-01-02 15:04 MST")),
+		Locale:  locale,
+		To:      member.Email,
+	}
+	event.Tenant, _ = domain.TenantFromContext(c)
+	if err = h.mailService.SendEmail(event); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// publishTransfer announces the transfer requested for a hold. The transfer is
+// already stored, so a failure is only logged.
+func (h *holdUseCase) publishTransfer(ctx context.Context, transfer domain.Transfer) {
+	content, err := json.Marshal(transfer)
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+	event := mb.Event{Content: string(content), Subject: string(mb.TRANSFER_REQUESTED), Locale: i18n.Locale(ctx)}
+	if principal, ok := domain.PrincipalFromContext(ctx); ok {
+		event.Actor = principal.Subject
+	}
+	event.Tenant, _ = domain.TenantFromContext(ctx)
+	if err = h.messageBroker.Send(event); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// RunExpiry expires the overdue holds every interval until ctx is done.
+func RunExpiry(ctx context.Context, us domain.HoldUseCase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := us.Expire(ctx); err != nil {
+				log.Println(err.Error())
+			} else if n > 0 {
+				log.Printf("%d holds expired", n)
+			}
+		}
+	}
+}
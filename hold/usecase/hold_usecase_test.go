@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	mb "github.com/bxcodec/library/message_broker"
+	"github.com/bxcodec/library/mocks"
+)
+
+type fixture struct {
+	holdRepo   *mocks.HoldRepository
+	copyRepo   *mocks.CopyRepository
+	memberRepo *mocks.MemberRepository
+	bookRepo   *mocks.BookRepository
+	mail       *mocks.MailService
+	broker     *mocks.MessageBroker
+	usecase    *holdUseCase
+}
+
+func newFixture(now time.Time) fixture {
+	f := fixture{
+		holdRepo:   new(mocks.HoldRepository),
+		copyRepo:   new(mocks.CopyRepository),
+		memberRepo: new(mocks.MemberRepository),
+		bookRepo:   new(mocks.BookRepository),
+		mail:       new(mocks.MailService),
+		broker:     new(mocks.MessageBroker),
+	}
+	f.usecase = NewHoldUseCase(f.holdRepo, f.copyRepo, f.memberRepo, f.bookRepo, f.mail, f.broker, 72*time.Hour, timeout.Every(time.Second)).(*holdUseCase)
+	f.usecase.now = func() time.Time { return now }
+	return f
+}
+
+func TestPlace(t *testing.T) {
+	f := newFixture(time.Now())
+	f.memberRepo.On("GetById", mock.Anything, 3).Return(domain.Member{ID: 3, Status: domain.MemberActive}, nil).Once()
+	f.holdRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.Hold")).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Hold).ID = 11 }).
+		Return(nil).Once()
+	f.copyRepo.On("FetchByBook", mock.Anything, 1).Return([]domain.Copy{{ID: 5, Status: domain.CopyOnLoan}}, nil).Once()
+	f.holdRepo.On("GetById", mock.Anything, 11).Return(domain.Hold{ID: 11, Status: domain.HoldWaiting, Position: 2}, nil).Once()
+
+	hold := domain.Hold{BookID: 1, MemberID: 3}
+	err := f.usecase.Place(context.TODO(), &hold)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, hold.Position)
+	f.holdRepo.AssertNotCalled(t, "AssignNext", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.holdRepo.AssertExpectations(t)
+}
+
+func TestAssignNotifiesMember(t *testing.T) {
+	now := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	expires := now.Add(72 * time.Hour)
+	copyID := 5
+	f := newFixture(now)
+	f.holdRepo.On("AssignNext", mock.Anything, 5, now, expires).
+		Return(domain.Hold{ID: 11, BookID: 1, MemberID: 3, CopyID: &copyID, Status: domain.HoldReady, ExpiresAt: &expires}, nil, nil).Once()
+	f.memberRepo.On("GetById", mock.Anything, 3).Return(domain.Member{ID: 3, Email: "ann@example.com"}, nil).Once()
+	f.bookRepo.On("GetById", mock.Anything, 1).Return(domain.Book{ID: 1, Title: "Makan Ayam"}, nil).Once()
+	f.mail.On("SendEmail", mock.MatchedBy(func(e mb.Event) bool {
+		return e.Subject == string(mb.HOLD_READY) && e.To == "ann@example.com"
+	})).Return(nil).Once()
+
+	require.NoError(t, f.usecase.Assign(context.TODO(), 5))
+	f.mail.AssertExpectations(t)
+}
+
+func TestAssignInNotifiesAfterCommit(t *testing.T) {
+	now := time.Now()
+	copyID := 5
+	f := newFixture(now)
+	txHolds := new(mocks.HoldRepository)
+	txHolds.On("AssignNext", mock.Anything, 5, mock.Anything, mock.Anything).
+		Return(domain.Hold{ID: 11, BookID: 1, MemberID: 3, CopyID: &copyID, Status: domain.HoldReady, ExpiresAt: &now}, nil, nil).Once()
+	f.memberRepo.On("GetById", mock.Anything, 3).Return(domain.Member{ID: 3, Email: "ann@example.com"}, nil).Once()
+	f.bookRepo.On("GetById", mock.Anything, 1).Return(domain.Book{ID: 1, Title: "Makan Ayam"}, nil).Once()
+	f.mail.On("SendEmail", mock.Anything).Return(nil).Once()
+
+	var committed []func()
+	repos := domain.Repositories{Holds: txHolds, AfterCommit: func(fn func()) { committed = append(committed, fn) }}
+	require.NoError(t, f.usecase.AssignIn(context.TODO(), repos, 5))
+	f.mail.AssertNotCalled(t, "SendEmail", mock.Anything)
+	f.holdRepo.AssertNotCalled(t, "AssignNext", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	require.Len(t, committed, 1)
+	committed[0]()
+	f.mail.AssertExpectations(t)
+}
+
+func TestAssignWithoutWaitingHold(t *testing.T) {
+	f := newFixture(time.Now())
+	f.holdRepo.On("AssignNext", mock.Anything, 5, mock.Anything, mock.Anything).Return(domain.Hold{}, nil, domain.ErrNotFound).Once()
+
+	assert.NoError(t, f.usecase.Assign(context.TODO(), 5))
+	f.mail.AssertNotCalled(t, "SendEmail", mock.Anything)
+}
+
+func TestExpirePassesCopyOn(t *testing.T) {
+	now := time.Now()
+	copyID := 5
+	f := newFixture(now)
+	f.holdRepo.On("Expire", mock.Anything, now.UTC()).Return([]domain.Hold{{ID: 11, CopyID: &copyID}, {ID: 12}}, nil).Once()
+	f.holdRepo.On("AssignNext", mock.Anything, 5, mock.Anything, mock.Anything).Return(domain.Hold{}, nil, domain.ErrNotFound).Once()
+
+	n, err := f.usecase.Expire(context.TODO())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	f.holdRepo.AssertExpectations(t)
+}
+
+func TestAssignRequestsTransfer(t *testing.T) {
+	holdID := 11
+	f := newFixture(time.Now())
+	f.holdRepo.On("AssignNext", mock.Anything, 5, mock.Anything, mock.Anything).
+		Return(domain.Hold{ID: holdID, Status: domain.HoldInTransit}, &domain.Transfer{ID: 8, CopyID: 5, HoldID: &holdID}, nil).Once()
+	f.broker.On("Send", mock.MatchedBy(func(e mb.Event) bool { return e.Subject == string(mb.TRANSFER_REQUESTED) })).Return(nil).Once()
+
+	require.NoError(t, f.usecase.Assign(context.TODO(), 5))
+	f.broker.AssertExpectations(t)
+	f.mail.AssertNotCalled(t, "SendEmail", mock.Anything)
+}
+
+func TestPlacePicksUpAtHomeBranch(t *testing.T) {
+	f := newFixture(time.Now())
+	f.memberRepo.On("GetById", mock.Anything, 3).Return(domain.Member{ID: 3, BranchID: 2, Status: domain.MemberActive}, nil).Once()
+	f.holdRepo.On("Add", mock.Anything, mock.MatchedBy(func(h *domain.Hold) bool { return h.PickupBranchID == 2 })).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Hold).ID = 11 }).
+		Return(nil).Once()
+	f.copyRepo.On("FetchByBook", mock.Anything, 1).Return([]domain.Copy{
+		{ID: 4, BranchID: 1, Status: domain.CopyAvailable},
+		{ID: 5, BranchID: 2, Status: domain.CopyAvailable},
+	}, nil).Once()
+	var assigned []int
+	f.holdRepo.On("AssignNext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { assigned = append(assigned, args.Int(1)) }).
+		Return(domain.Hold{}, nil, domain.ErrNotFound)
+	f.holdRepo.On("GetById", mock.Anything, 11).Return(domain.Hold{ID: 11, PickupBranchID: 2, Status: domain.HoldWaiting}, nil).Once()
+
+	hold := domain.Hold{BookID: 1, MemberID: 3}
+	require.NoError(t, f.usecase.Place(context.TODO(), &hold))
+
+	assert.Equal(t, []int{5, 4}, assigned)
+	f.holdRepo.AssertExpectations(t)
+}
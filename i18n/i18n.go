@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/labstack/echo"
+	"golang.org/x/text/language"
+)
+
+const (
+	English = "en"
+	Russian = "ru"
+
+	HeaderAcceptLanguage  = "Accept-Language"
+	HeaderContentLanguage = "Content-Language"
+)
+
+type localeKey struct{}
+
+var universal = ut.New(en.New(), en.New(), ru.New())
+
+func init() {
+	for locale, translations := range errorMessages {
+		for err, text := range translations {
+			if e := Translator(locale).Add(err, text, false); e != nil {
+				panic(e)
+			}
+		}
+	}
+	for locale, translations := range messages {
+		for key, text := range translations {
+			if err := Translator(locale).Add(key, text, false); err != nil {
+				panic(err)
+			}
+		}
+	}
+}
+
+// Translator returns the translator of locale, falling back to English when
+// the locale is not supported.
+func Translator(locale string) ut.Translator {
+	trans, _ := universal.GetTranslator(locale)
+	return trans
+}
+
+// Negotiate picks the supported locale best matching an Accept-Language
+// header value, English when none of the requested languages is supported.
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return English
+	}
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		if _, found := universal.GetTranslator(base.String()); found {
+			return base.String()
+		}
+	}
+	return English
+}
+
+// Middleware negotiates the locale of every request from its Accept-Language
+// header and stores it in the request context.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			locale := Negotiate(c.Request().Header.Get(HeaderAcceptLanguage))
+			c.SetRequest(c.Request().WithContext(WithLocale(c.Request().Context(), locale)))
+			c.Response().Header().Set(HeaderContentLanguage, locale)
+			c.Response().Header().Add(echo.HeaderVary, HeaderAcceptLanguage)
+			return next(c)
+		}
+	}
+}
+
+// WithLocale returns a copy of ctx carrying locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// Locale returns the locale carried by ctx, an empty string when there is none.
+func Locale(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	return locale
+}
+
+// Error returns the message of the first known error err wraps, translated to
+// locale. Unknown errors are returned untranslated.
+func Error(locale string, err error) string {
+	for known := range errorMessages[English] {
+		if errors.Is(err, known) {
+			if msg, e := Translator(locale).T(known); e == nil {
+				return msg
+			}
+		}
+	}
+	return err.Error()
+}
+
+// T translates key to locale, returning the key itself when it has no
+// translation.
+func T(locale string, key string, params ...string) string {
+	msg, err := Translator(locale).T(key, params...)
+	if err != nil {
+		return key
+	}
+	return msg
+}
@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"github.com/bxcodec/library/domain"
+)
+
+const (
+	ValidationFailed = "validation.failed"
+	InvalidID        = "validation.invalid_id"
+	HoldReady        = "hold.ready"
+	LoanDueSoon      = "loan.due_soon"
+	LoanOverdue      = "loan.overdue"
+)
+
+// errorMessages holds the translations of the domain errors, keyed by locale.
+var errorMessages = map[string]map[error]string{
+	English: {
+		domain.ErrInternalServerError: domain.ErrInternalServerError.Error(),
+		domain.ErrNotFound:            domain.ErrNotFound.Error(),
+		domain.ErrConflict:            domain.ErrConflict.Error(),
+		domain.ErrVersionConflict:     domain.ErrVersionConflict.Error(),
+		domain.ErrInvalidPatch:        domain.ErrInvalidPatch.Error(),
+		domain.ErrUnauthorized:        domain.ErrUnauthorized.Error(),
+		domain.ErrForbidden:           domain.ErrForbidden.Error(),
+		domain.ErrNotAvailable:        domain.ErrNotAvailable.Error(),
+		domain.ErrRenewalLimit:        domain.ErrRenewalLimit.Error(),
+		domain.ErrMembershipInactive:  domain.ErrMembershipInactive.Error(),
+		domain.ErrFinesOutstanding:    domain.ErrFinesOutstanding.Error(),
+		domain.ErrUnknownTenant:       domain.ErrUnknownTenant.Error(),
+	},
+	Russian: {
+		domain.ErrInternalServerError: "внутренняя ошибка сервера",
+		domain.ErrNotFound:            "запрошенный объект не найден",
+		domain.ErrConflict:            "объект уже существует",
+		domain.ErrVersionConflict:     "книга была изменена другим запросом",
+		domain.ErrInvalidPatch:        "изменения не могут быть применены к книге",
+		domain.ErrUnauthorized:        "требуется аутентификация",
+		domain.ErrForbidden:           "операция запрещена",
+		domain.ErrNotAvailable:        "экземпляр недоступен",
+		domain.ErrRenewalLimit:        "выдачу больше нельзя продлить",
+		domain.ErrMembershipInactive:  "читательский билет не действителен",
+		domain.ErrFinesOutstanding:    "сумма неоплаченных штрафов превышает лимит",
+		domain.ErrUnknownTenant:       "библиотека не найдена",
+	},
+}
+
+// messages holds the translations of the other texts shown to API clients,
+// keyed by locale.
+var messages = map[string]map[string]string{
+	English: {
+		ValidationFailed: "request is not valid",
+		InvalidID:        "{0} must be an integer id",
+		HoldReady:        "\"{0}\" is waiting for you at the library until {1}.",
+		LoanDueSoon:      "\"{0}\" is due back on {1}.",
+		LoanOverdue:      "\"{0}\" was due back on {1}, the fine so far is {2}.",
+	},
+	Russian: {
+		ValidationFailed: "запрос содержит ошибки",
+		InvalidID:        "{0} должен быть целым идентификатором",
+		HoldReady:        "Книга «{0}» ждет вас в библиотеке до {1}.",
+		LoanDueSoon:      "Книгу «{0}» нужно вернуть до {1}.",
+		LoanOverdue:      "Книгу «{0}» нужно было вернуть до {1}, штраф составляет {2}.",
+	},
+}
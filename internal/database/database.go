@@ -0,0 +1,82 @@
+// Package database describes the connections of the service to its Postgres
+// databases.
+package database
+
+import (
+	"database/sql"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Config describes the connection to a Postgres database.
+type Config struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	Name     string
+	// SSLMode is the sslmode of lib/pq, such as disable or verify-full.
+	SSLMode string
+	// TimeZone is the time zone of the sessions, which every timestamp read
+	// is returned in.
+	TimeZone string
+	// ConnectTimeout bounds the time to connect, rounded up to the second.
+	ConnectTimeout time.Duration
+	// StatementTimeout aborts the statements running longer, in the database.
+	StatementTimeout time.Duration
+}
+
+// DSN returns the url of the database, its parts escaped. The zero settings
+// are left out so that the defaults of lib/pq and of the server apply.
+func (c Config) DSN() string {
+	u := url.URL{
+		Scheme:  "postgres",
+		User:    url.UserPassword(c.User, c.Password),
+		Host:    net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:    "/" + c.Name,
+		RawPath: "/" + url.PathEscape(c.Name),
+	}
+	query := url.Values{}
+	if c.SSLMode != "" {
+		query.Set("sslmode", c.SSLMode)
+	}
+	if c.TimeZone != "" {
+		query.Set("timezone", c.TimeZone)
+	}
+	if c.ConnectTimeout > 0 {
+		query.Set("connect_timeout", strconv.Itoa(int(math.Ceil(c.ConnectTimeout.Seconds()))))
+	}
+	if c.StatementTimeout > 0 {
+		query.Set("statement_timeout", strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10))
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// Pool configures the connections a sql.DB keeps. The zero settings keep the
+// defaults of database/sql.
+type Pool struct {
+	MaxOpen     int
+	MaxIdle     int
+	MaxLifetime time.Duration
+	MaxIdleTime time.Duration
+}
+
+// Apply configures the pool of db.
+func (p Pool) Apply(db *sql.DB) {
+	if p.MaxOpen > 0 {
+		db.SetMaxOpenConns(p.MaxOpen)
+	}
+	if p.MaxIdle > 0 {
+		db.SetMaxIdleConns(p.MaxIdle)
+	}
+	if p.MaxLifetime > 0 {
+		db.SetConnMaxLifetime(p.MaxLifetime)
+	}
+	if p.MaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(p.MaxIdleTime)
+	}
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDSNEscapes(t *testing.T) {
+	dsn := Config{
+		Host:             "db.local",
+		Port:             5432,
+		User:             "lib rary",
+		Password:         "p@ss:/word?#",
+		Name:             "my/library",
+		SSLMode:          "verify-full",
+		TimeZone:         "Europe/Moscow",
+		ConnectTimeout:   1500 * time.Millisecond,
+		StatementTimeout: 30 * time.Second,
+	}.DSN()
+
+	settings, err := pq.ParseURL(dsn)
+	require.NoError(t, err)
+	assert.Equal(t, "connect_timeout='2' dbname='my/library' host='db.local' password='p@ss:/word?#' port='5432' "+
+		"sslmode='verify-full' statement_timeout='30000' timezone='Europe/Moscow' user='lib rary'", settings)
+}
+
+func TestDSNLeavesOutZeroSettings(t *testing.T) {
+	dsn := Config{Host: "::1", Port: 5432, User: "library", Name: "postgres"}.DSN()
+	assert.Equal(t, "postgres://library:@[::1]:5432/postgres", dsn)
+}
+
+func TestPoolApply(t *testing.T) {
+	db, err := sql.Open("postgres", Config{Host: "localhost", Port: 5432}.DSN())
+	require.NoError(t, err)
+	defer db.Close()
+
+	Pool{MaxOpen: 7}.Apply(db)
+	assert.Equal(t, 7, db.Stats().MaxOpenConnections)
+	Pool{}.Apply(db)
+	assert.Equal(t, 7, db.Stats().MaxOpenConnections)
+}
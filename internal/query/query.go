@@ -0,0 +1,152 @@
+// Package query builds the SELECT statements of the Postgres repositories.
+// Values are always passed as numbered placeholders and the columns a result
+// may be sorted by are whitelisted, so no input ends up in the SQL text.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Direction is the order of a sort.
+type Direction string
+
+const (
+	Asc  Direction = "ASC"
+	Desc Direction = "DESC"
+)
+
+// Columns maps the names a result may be sorted by onto the columns holding
+// them.
+type Columns map[string]string
+
+// Builder composes a SELECT statement. The first error is kept and reported
+// by Build.
+type Builder struct {
+	columns  []string
+	from     string
+	joins    []string
+	where    []string
+	orderBy  []string
+	sortable Columns
+	limit    int
+	offset   int
+	args     []interface{}
+	err      error
+}
+
+// Select starts a statement selecting columns.
+func Select(columns ...string) *Builder {
+	return &Builder{columns: columns}
+}
+
+// From sets the table selected from.
+func (b *Builder) From(table string) *Builder {
+	b.from = table
+	return b
+}
+
+// Join adds a join clause, such as "INNER JOIN author a ON b.author_id = a.id".
+func (b *Builder) Join(clause string) *Builder {
+	b.joins = append(b.joins, clause)
+	return b
+}
+
+// Where adds a condition, combined with the others by AND with each of them
+// parenthesized. Every ? of the condition is a placeholder bound to the next
+// of args.
+func (b *Builder) Where(condition string, args ...interface{}) *Builder {
+	if n := strings.Count(condition, "?"); n != len(args) {
+		b.fail(fmt.Errorf("query: condition %q has %d placeholders for %d args", condition, n, len(args)))
+		return b
+	}
+	var sb strings.Builder
+	for _, r := range condition {
+		if r != '?' {
+			sb.WriteRune(r)
+			continue
+		}
+		b.args = append(b.args, args[0])
+		args = args[1:]
+		sb.WriteString("$" + strconv.Itoa(len(b.args)))
+	}
+	b.where = append(b.where, sb.String())
+	return b
+}
+
+// Sortable whitelists the names OrderBy accepts.
+func (b *Builder) Sortable(columns Columns) *Builder {
+	b.sortable = columns
+	return b
+}
+
+// OrderBy sorts the result by the column whitelisted under name, after the
+// sorts added before.
+func (b *Builder) OrderBy(name string, direction Direction) *Builder {
+	column, ok := b.sortable[name]
+	if !ok {
+		b.fail(fmt.Errorf("query: can not sort by %q", name))
+		return b
+	}
+	if direction != Asc && direction != Desc {
+		b.fail(fmt.Errorf("query: unknown sort direction %q", direction))
+		return b
+	}
+	b.orderBy = append(b.orderBy, column+" "+string(direction))
+	return b
+}
+
+// Limit returns at most n rows, zero means no limit.
+func (b *Builder) Limit(n int) *Builder {
+	b.limit = n
+	return b
+}
+
+// Offset skips the first n rows.
+func (b *Builder) Offset(n int) *Builder {
+	b.offset = n
+	return b
+}
+
+// Build returns the statement and the args of its placeholders.
+func (b *Builder) Build() (string, []interface{}, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if len(b.columns) == 0 || b.from == "" {
+		return "", nil, fmt.Errorf("query: columns and table are required")
+	}
+
+	args := append([]interface{}(nil), b.args...)
+	var sb strings.Builder
+	sb.WriteString("SELECT " + strings.Join(b.columns, ", ") + " FROM " + b.from)
+	for _, join := range b.joins {
+		sb.WriteString(" " + join)
+	}
+	switch len(b.where) {
+	case 0:
+	case 1:
+		sb.WriteString(" WHERE " + b.where[0])
+	default:
+		sb.WriteString(" WHERE (" + strings.Join(b.where, ") AND (") + ")")
+	}
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY " + strings.Join(b.orderBy, ", "))
+	}
+	if b.limit > 0 {
+		args = append(args, b.limit)
+		sb.WriteString(" LIMIT $" + strconv.Itoa(len(args)))
+	}
+	if b.offset > 0 {
+		args = append(args, b.offset)
+		sb.WriteString(" OFFSET $" + strconv.Itoa(len(args)))
+	}
+	return sb.String(), args, nil
+}
+
+func (b *Builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
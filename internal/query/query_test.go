@@ -0,0 +1,47 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var columns = Columns{"id": "b.id", "title": "b.title"}
+
+func TestBuild(t *testing.T) {
+	stmt, args, err := Select("b.id", "b.title").
+		From("book b").
+		Join("INNER JOIN author a ON b.author_id = a.id").
+		Where("b.author_id = ?", 3).
+		Where("b.title ILIKE ? OR b.content ILIKE ?", "%go%", "%go%").
+		Sortable(columns).
+		OrderBy("title", Desc).
+		OrderBy("id", Asc).
+		Limit(10).
+		Offset(20).
+		Build()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT b.id, b.title FROM book b INNER JOIN author a ON b.author_id = a.id "+
+		"WHERE (b.author_id = $1) AND (b.title ILIKE $2 OR b.content ILIKE $3) ORDER BY b.title DESC, b.id ASC LIMIT $4 OFFSET $5", stmt)
+	assert.Equal(t, []interface{}{3, "%go%", "%go%", 10, 20}, args)
+}
+
+func TestBuildRejectsUnsafeInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *Builder
+	}{
+		{name: "unknown sort column", builder: Select("id").From("book").Sortable(columns).OrderBy("id; DROP TABLE book", Asc)},
+		{name: "unknown sort direction", builder: Select("id").From("book").Sortable(columns).OrderBy("id", Direction("ASC; --"))},
+		{name: "missing args", builder: Select("id").From("book").Where("id = ? AND version = ?", 1)},
+		{name: "missing table", builder: Select("id")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := tt.builder.Build()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Statements prepares every statement once per database and reuses it for the
+// following calls. It is safe for concurrent use.
+type Statements struct {
+	mu    sync.RWMutex
+	stmts map[statement]*sql.Stmt
+}
+
+type statement struct {
+	db   *sql.DB
+	text string
+}
+
+func NewStatements() *Statements {
+	return &Statements{stmts: make(map[statement]*sql.Stmt)}
+}
+
+// Prepare returns the statement prepared for text on db, preparing it on first
+// use. The statement is prepared without holding the lock, so a slow database
+// only delays its own first uses; of concurrent first uses, the statement
+// stored first is kept and the others are closed.
+func (s *Statements) Prepare(ctx context.Context, db *sql.DB, text string) (*sql.Stmt, error) {
+	key := statement{db: db, text: text}
+	s.mu.RLock()
+	stmt, ok := s.stmts[key]
+	s.mu.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+
+	prepared, err := db.PrepareContext(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	stmt, ok = s.stmts[key]
+	if !ok {
+		s.stmts[key] = prepared
+	}
+	s.mu.Unlock()
+	if ok {
+		if err = prepared.Close(); err != nil {
+			logrus.Error(err)
+		}
+		return stmt, nil
+	}
+	return prepared, nil
+}
+
+// Close closes every prepared statement and returns the first error.
+func (s *Statements) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var first error
+	for key, stmt := range s.stmts {
+		if err := stmt.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(s.stmts, key)
+	}
+	return first
+}
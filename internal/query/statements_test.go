@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestPrepareDoesNotWaitForOtherStatements(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectPrepare("SELECT 1")
+	mock.ExpectPrepare("SELECT 2").WillDelayFor(time.Second)
+
+	stmts := NewStatements()
+	first, err := stmts.Prepare(context.TODO(), db, "SELECT 1")
+	require.NoError(t, err)
+
+	prepared := make(chan struct{})
+	go func() {
+		defer close(prepared)
+		_, err := stmts.Prepare(context.TODO(), db, "SELECT 2")
+		assert.NoError(t, err)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	again, err := stmts.Prepare(context.TODO(), db, "SELECT 1")
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+	select {
+	case <-prepared:
+		t.Fatal("the cached statement waited for another one to be prepared")
+	default:
+	}
+	<-prepared
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
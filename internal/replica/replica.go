@@ -0,0 +1,123 @@
+// Package replica routes the statements of repositories to a primary database
+// and its read replicas.
+package replica
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// Cluster is a primary database and its read replicas. Writes go to the
+// primary and reads to the healthy replicas in turn, or to the primary when
+// none is healthy or the context asks for it with domain.NewContextWithPrimary.
+// It is safe for concurrent use.
+type Cluster struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     uint32
+}
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy int32
+}
+
+// New returns the cluster of primary and the replicas by name. Replicas are
+// taken as healthy until a check fails.
+func New(primary *sql.DB, replicas map[string]*sql.DB) *Cluster {
+	c := &Cluster{primary: primary}
+	for name, db := range replicas {
+		c.replicas = append(c.replicas, &replica{name: name, db: db, healthy: 1})
+	}
+	sort.Slice(c.replicas, func(i, j int) bool { return c.replicas[i].name < c.replicas[j].name })
+	return c
+}
+
+// Primary returns the database writes go to.
+func (c *Cluster) Primary() *sql.DB {
+	return c.primary
+}
+
+// Reader returns the database the reads of ctx go to.
+func (c *Cluster) Reader(ctx context.Context) *sql.DB {
+	if len(c.replicas) == 0 || domain.PrimaryFromContext(ctx) {
+		return c.primary
+	}
+	start := atomic.AddUint32(&c.next, 1)
+	for i := range c.replicas {
+		r := c.replicas[(int(start)+i)%len(c.replicas)]
+		if atomic.LoadInt32(&r.healthy) == 1 {
+			return r.db
+		}
+	}
+	return c.primary
+}
+
+// Check pings every replica and routes reads to those that answered.
+func (c *Cluster) Check(ctx context.Context) {
+	for _, r := range c.replicas {
+		healthy := int32(1)
+		if err := r.db.PingContext(ctx); err != nil {
+			healthy = 0
+			logrus.Warnf("replica %s is unhealthy: %s", r.name, err)
+		}
+		if previous := atomic.SwapInt32(&r.healthy, healthy); previous == 0 && healthy == 1 {
+			logrus.Infof("replica %s is healthy", r.name)
+		}
+	}
+}
+
+// Run checks the replicas every interval until ctx is done, each check given
+// the interval to complete.
+func (c *Cluster) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		check, cancel := context.WithTimeout(ctx, interval)
+		c.Check(check)
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stats are the pool statistics of a database of a cluster and whether it is
+// taking reads.
+type Stats struct {
+	sql.DBStats
+	Healthy bool
+}
+
+// Stats returns the statistics of the primary, as "primary", and of every
+// replica by name.
+func (c *Cluster) Stats() map[string]Stats {
+	stats := map[string]Stats{"primary": {DBStats: c.primary.Stats(), Healthy: true}}
+	for _, r := range c.replicas {
+		stats[r.name] = Stats{DBStats: r.db.Stats(), Healthy: atomic.LoadInt32(&r.healthy) == 1}
+	}
+	return stats
+}
+
+// Close closes the replicas, the primary is left to its owner.
+func (c *Cluster) Close() error {
+	var first error
+	for _, r := range c.replicas {
+		if err := r.db.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,75 @@
+package replica
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+)
+
+func newDB(t *testing.T) *sql.DB {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	return db
+}
+
+// closedDB returns a database failing every ping.
+func closedDB(t *testing.T) *sql.DB {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectClose()
+	require.NoError(t, db.Close())
+	return db
+}
+
+func TestReaderWithoutReplicas(t *testing.T) {
+	primary := newDB(t)
+	c := New(primary, nil)
+
+	assert.Same(t, primary, c.Reader(context.TODO()))
+	assert.Same(t, primary, c.Primary())
+}
+
+func TestReaderRoundRobin(t *testing.T) {
+	primary, a, b := newDB(t), newDB(t), newDB(t)
+	c := New(primary, map[string]*sql.DB{"a": a, "b": b})
+
+	first := c.Reader(context.TODO())
+	second := c.Reader(context.TODO())
+	assert.ElementsMatch(t, []*sql.DB{a, b}, []*sql.DB{first, second})
+	assert.Same(t, first, c.Reader(context.TODO()))
+	assert.Same(t, primary, c.Reader(domain.NewContextWithPrimary(context.TODO())))
+}
+
+func TestReaderSkipsUnhealthyReplicas(t *testing.T) {
+	primary, a, b := newDB(t), newDB(t), closedDB(t)
+	c := New(primary, map[string]*sql.DB{"a": a, "b": b})
+
+	c.Check(context.TODO())
+	for i := 0; i < 3; i++ {
+		assert.Same(t, a, c.Reader(context.TODO()))
+	}
+
+	c = New(primary, map[string]*sql.DB{"b": b})
+	c.Check(context.TODO())
+	assert.Same(t, primary, c.Reader(context.TODO()))
+}
+
+func TestStats(t *testing.T) {
+	primary, a, b := newDB(t), newDB(t), closedDB(t)
+	primary.SetMaxOpenConns(5)
+	c := New(primary, map[string]*sql.DB{"a": a, "b": b})
+	c.Check(context.TODO())
+
+	stats := c.Stats()
+	assert.Len(t, stats, 3)
+	assert.Equal(t, 5, stats["primary"].MaxOpenConnections)
+	assert.True(t, stats["primary"].Healthy)
+	assert.True(t, stats["a"].Healthy)
+	assert.False(t, stats["b"].Healthy)
+}
@@ -0,0 +1,89 @@
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/bxcodec/library/migrations"
+)
+
+// EnvPostgres names the variable holding the url of the Postgres database the
+// contract runs against. The tests using it are skipped when it is unset.
+const EnvPostgres = "LIBRARY_TEST_POSTGRES"
+
+// lockID serializes the packages running the contract on the same Postgres
+// database.
+const lockID = 72176346
+
+// SQLite returns a migrated in-memory SQLite database holding an author per
+// name.
+func SQLite(t *testing.T, authors ...string) *sql.DB {
+	db, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	// Every connection opens a database of its own.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	migrate(t, db, migrations.SQLite)
+	addAuthors(t, db, authors)
+	return db
+}
+
+// Postgres returns the migrated database of EnvPostgres emptied of books and
+// authors and holding an author per name. The url is of the owner of the
+// schema, its rows belong to the contract tenant.
+func Postgres(t *testing.T, authors ...string) *sql.DB {
+	raw := os.Getenv(EnvPostgres)
+	if raw == "" {
+		t.Skip(EnvPostgres + " is not set")
+	}
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	q := u.Query()
+	q.Set("options", "-c app.tenant_id=contract")
+	u.RawQuery = q.Encode()
+
+	db, err := sql.Open("postgres", u.String())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.TODO()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+		conn.Close()
+	})
+
+	migrate(t, db, migrations.Postgres)
+	_, err = db.ExecContext(ctx, `TRUNCATE book, author RESTART IDENTITY CASCADE`)
+	require.NoError(t, err)
+	addAuthors(t, db, authors)
+	return db
+}
+
+func migrate(t *testing.T, db *sql.DB, dialect migrations.Dialect) {
+	m, err := migrations.NewMigrator(db, dialect)
+	require.NoError(t, err)
+	_, err = m.Up(context.TODO())
+	require.NoError(t, err)
+}
+
+func addAuthors(t *testing.T, db *sql.DB, authors []string) {
+	for i, name := range authors {
+		var id int
+		err := db.QueryRow(`INSERT INTO author (name) VALUES ($1) RETURNING id`, name).Scan(&id)
+		require.NoError(t, err)
+		require.Equal(t, i+1, id, fmt.Sprintf("author %q", name))
+	}
+}
@@ -0,0 +1,265 @@
+// Package repotest is the contract of the book and author repositories:
+// ordering, paging, missing rows, version conflicts and concurrent use. Every
+// backend runs it against a store of its own, so that they behave alike.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// Books returns the book repository of an empty database holding an author per
+// name, with the ids 1, 2, ... in order.
+type Books func(t *testing.T, authors ...string) domain.BookRepository
+
+// Authors returns the author repository of an empty database holding an author
+// per name, with the ids 1, 2, ... in order.
+type Authors func(t *testing.T, authors ...string) domain.AuthorRepository
+
+// BookRepository runs the contract of domain.BookRepository.
+func BookRepository(t *testing.T, open Books) {
+	ctx := context.TODO()
+
+	t.Run("AddAndGet", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang")
+		book := domain.Book{Title: "Makan Ayam", Content: "content", Author: domain.Author{ID: 1}}
+		require.NoError(t, repo.Add(ctx, &book))
+		assert.NotZero(t, book.ID)
+		assert.Equal(t, 1, book.Version)
+		assert.False(t, book.CreatedAt.IsZero())
+		assert.True(t, book.UpdatedAt.Equal(book.CreatedAt))
+
+		got, err := repo.GetById(ctx, book.ID)
+		require.NoError(t, err)
+		assert.Equal(t, utc(book), utc(got))
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		repo := open(t)
+		_, err := repo.GetById(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("Fetch", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang")
+		for _, title := range []string{"one", "two", "three"} {
+			require.NoError(t, repo.Add(ctx, &domain.Book{Title: title, Content: title, Author: domain.Author{ID: 1}}))
+		}
+
+		all, err := repo.Fetch(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two", "three"}, titles(all))
+
+		page, err := repo.Fetch(ctx, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two"}, titles(page))
+
+		page, err = repo.Fetch(ctx, 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"three"}, titles(page))
+		assert.Equal(t, 1, page[0].Author.ID)
+
+		page, err = repo.Fetch(ctx, 2, 3)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+
+		page, err = repo.Fetch(ctx, 2, -1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "two"}, titles(page))
+
+		require.NoError(t, repo.Delete(ctx, all[1].ID, 0))
+		page, err = repo.Fetch(ctx, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"one", "three"}, titles(page))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang", "Arthur Conan Doyle")
+		book := domain.Book{Title: "Makan Ayam", Content: "content", Author: domain.Author{ID: 1}}
+		require.NoError(t, repo.Add(ctx, &book))
+		created := book.CreatedAt
+		time.Sleep(10 * time.Millisecond)
+
+		book.Title, book.Author.ID, book.CreatedAt = "Makan Ikan", 2, time.Time{}
+		require.NoError(t, repo.Update(ctx, &book))
+		assert.Equal(t, 2, book.Version)
+		assert.True(t, book.CreatedAt.Equal(created))
+		assert.True(t, book.UpdatedAt.After(created))
+
+		got, err := repo.GetById(ctx, book.ID)
+		require.NoError(t, err)
+		assert.Equal(t, utc(book), utc(got))
+
+		stale := book
+		stale.Version = 1
+		assert.ErrorIs(t, repo.Update(ctx, &stale), domain.ErrVersionConflict)
+
+		book.Version = 0
+		require.NoError(t, repo.Update(ctx, &book))
+		assert.Equal(t, 3, book.Version)
+
+		missing := domain.Book{ID: book.ID + 1, Title: "t", Content: "c"}
+		assert.ErrorIs(t, repo.Update(ctx, &missing), domain.ErrNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang")
+		book := domain.Book{Title: "Makan Ayam", Content: "content", Author: domain.Author{ID: 1}}
+		require.NoError(t, repo.Add(ctx, &book))
+
+		assert.ErrorIs(t, repo.Delete(ctx, book.ID, book.Version+1), domain.ErrVersionConflict)
+		require.NoError(t, repo.Delete(ctx, book.ID, book.Version))
+		assert.ErrorIs(t, repo.Delete(ctx, book.ID, 0), domain.ErrNotFound)
+
+		_, err := repo.GetById(ctx, book.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("ConcurrentAdd", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang")
+		const n = 20
+		ids := make(chan int, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				book := domain.Book{Title: fmt.Sprint("book ", i), Content: "content", Author: domain.Author{ID: 1}}
+				if assert.NoError(t, repo.Add(ctx, &book)) {
+					ids <- book.ID
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(ids)
+
+		seen := make(map[int]bool)
+		for id := range ids {
+			assert.False(t, seen[id], "id %d is given twice", id)
+			seen[id] = true
+		}
+		all, err := repo.Fetch(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, all, n)
+	})
+
+	t.Run("ConcurrentUpdate", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang")
+		book := domain.Book{Title: "Makan Ayam", Content: "content", Author: domain.Author{ID: 1}}
+		require.NoError(t, repo.Add(ctx, &book))
+
+		const n = 10
+		errs := make(chan error, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				update := book
+				update.Title = fmt.Sprint("title ", i)
+				errs <- repo.Update(ctx, &update)
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+
+		var updated int
+		for err := range errs {
+			if err == nil {
+				updated++
+				continue
+			}
+			assert.ErrorIs(t, err, domain.ErrVersionConflict)
+		}
+		assert.Equal(t, 1, updated)
+
+		got, err := repo.GetById(ctx, book.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.Version)
+	})
+}
+
+// BookAuthors runs the contract of the book repositories of a database, which
+// keeps a book from referencing a missing author. The in-memory repository
+// holds no authors and does not run it.
+func BookAuthors(t *testing.T, open Books) {
+	ctx := context.TODO()
+
+	t.Run("AddUnknownAuthor", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang")
+		book := domain.Book{Title: "Makan Ayam", Content: "content", Author: domain.Author{ID: 2}}
+		assert.ErrorIs(t, repo.Add(ctx, &book), domain.ErrNotFound)
+
+		all, err := repo.Fetch(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("UpdateUnknownAuthor", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang")
+		book := domain.Book{Title: "Makan Ayam", Content: "content", Author: domain.Author{ID: 1}}
+		require.NoError(t, repo.Add(ctx, &book))
+
+		update := book
+		update.Author.ID = 2
+		assert.ErrorIs(t, repo.Update(ctx, &update), domain.ErrNotFound)
+
+		got, err := repo.GetById(ctx, book.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, got.Author.ID)
+		assert.Equal(t, 1, got.Version)
+	})
+}
+
+// AuthorRepository runs the contract of domain.AuthorRepository.
+func AuthorRepository(t *testing.T, open Authors) {
+	ctx := context.TODO()
+
+	t.Run("GetById", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang")
+		author, err := repo.GetById(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, author.ID)
+		assert.Equal(t, "Iman Tumorang", author.Name)
+		assert.False(t, author.CreatedAt.IsZero())
+
+		_, err = repo.GetById(ctx, 2)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("FetchByIDs", func(t *testing.T) {
+		repo := open(t, "Iman Tumorang", "Arthur Conan Doyle", "Agatha Christie")
+		authors, err := repo.FetchByIDs(ctx, []int{3, 1, 9})
+		require.NoError(t, err)
+		require.Len(t, authors, 2)
+		assert.Equal(t, "Agatha Christie", authors[3].Name)
+		assert.Equal(t, "Iman Tumorang", authors[1].Name)
+
+		authors, err = repo.FetchByIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, authors)
+	})
+}
+
+// utc returns book with its times in UTC, as databases return them in the
+// time zone of the session.
+func utc(book domain.Book) domain.Book {
+	book.CreatedAt, book.UpdatedAt = book.CreatedAt.UTC(), book.UpdatedAt.UTC()
+	return book
+}
+
+func titles(books []domain.Book) []string {
+	result := make([]string, len(books))
+	for i, book := range books {
+		result[i] = book.Title
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+// Package timeout gives the operations of the use cases their time.
+package timeout
+
+import "time"
+
+// Timeouts are the times the operations of a use case are given.
+type Timeouts struct {
+	// Default is the time of the operations missing from Operations.
+	Default time.Duration
+	// Operations are the times of operations by the snake_case name of their
+	// method, such as "get_by_id".
+	Operations map[string]time.Duration
+}
+
+// Every returns the timeouts giving every operation d.
+func Every(d time.Duration) Timeouts {
+	return Timeouts{Default: d}
+}
+
+// Of returns the time of the operation.
+func (t Timeouts) Of(operation string) time.Duration {
+	if d, ok := t.Operations[operation]; ok {
+		return d
+	}
+	return t.Default
+}
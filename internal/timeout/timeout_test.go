@@ -0,0 +1,16 @@
+package timeout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOf(t *testing.T) {
+	timeouts := Timeouts{Default: time.Second, Operations: map[string]time.Duration{"fetch": 3 * time.Second}}
+
+	assert.Equal(t, 3*time.Second, timeouts.Of("fetch"))
+	assert.Equal(t, time.Second, timeouts.Of("get_by_id"))
+	assert.Equal(t, time.Second, Every(time.Second).Of("fetch"))
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/validation"
+)
+
+const (
+	NUM    = "num"
+	OFFSET = "offset"
+	ID     = "id"
+)
+
+type LoanHandler struct {
+	LUseCase  domain.LoanUseCase
+	validator *validator.Validate
+}
+
+// NewLoanHandler registers the loan routes on e, each of them wrapped in
+// middleware.
+func NewLoanHandler(e *echo.Echo, us domain.LoanUseCase, middleware ...echo.MiddlewareFunc) {
+	handler := &LoanHandler{LUseCase: us, validator: validation.Validator()}
+	handler.Register(e.Group("/api/v1", middleware...))
+}
+
+func (h *LoanHandler) Register(g *echo.Group) {
+	g.POST("/loans", h.Checkout)
+	g.GET("/loans/:id", h.GetById)
+	g.POST("/loans/:id/return", h.Return)
+	g.POST("/loans/:id/renew", h.Renew)
+	g.GET("/members/:id/loans", h.FetchByMember)
+	g.GET("/books/:id/loans", h.FetchByBook)
+}
+
+func (h LoanHandler) Checkout(c echo.Context) error {
+	var loan domain.Loan
+	if err := c.Bind(&loan); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(&loan); err != nil {
+		return err
+	}
+
+	if err := h.LUseCase.Checkout(c.Request().Context(), &loan); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, loan)
+}
+
+func (h LoanHandler) GetById(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	loan, err := h.LUseCase.GetById(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, loan)
+}
+
+func (h LoanHandler) Return(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	loan, err := h.LUseCase.Return(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, loan)
+}
+
+func (h LoanHandler) Renew(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	loan, err := h.LUseCase.Renew(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, loan)
+}
+
+func (h LoanHandler) FetchByMember(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+	num, _ := strconv.Atoi(c.QueryParam(NUM))
+	offset, _ := strconv.Atoi(c.QueryParam(OFFSET))
+
+	loans, err := h.LUseCase.FetchByMember(c.Request().Context(), id, num, offset)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, loans)
+}
+
+func (h LoanHandler) FetchByBook(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+	num, _ := strconv.Atoi(c.QueryParam(NUM))
+	offset, _ := strconv.Atoi(c.QueryParam(OFFSET))
+
+	loans, err := h.LUseCase.FetchByBook(c.Request().Context(), id, num, offset)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, loans)
+}
+
+func pathID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param(ID))
+	if err != nil {
+		return 0, &domain.InvalidIDError{Param: ID, Value: c.Param(ID)}
+	}
+	return id, nil
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/validation"
+)
+
+func TestCheckout(t *testing.T) {
+	mockUCase := new(mocks.LoanUseCase)
+	mockUCase.On("Checkout", mock.Anything, &domain.Loan{CopyID: 5, MemberID: 3}).Return(nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/loans", strings.NewReader(`{"copy_id":5,"member_id":3}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := LoanHandler{LUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, handler.Checkout(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestCheckoutUnavailable(t *testing.T) {
+	mockUCase := new(mocks.LoanUseCase)
+	mockUCase.On("Checkout", mock.Anything, mock.AnythingOfType("*domain.Loan")).Return(domain.ErrNotAvailable).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/loans", strings.NewReader(`{"copy_id":5,"member_id":3}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := LoanHandler{LUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.Checkout)(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/problems/not-available")
+}
+
+func TestRenewLimit(t *testing.T) {
+	mockUCase := new(mocks.LoanUseCase)
+	mockUCase.On("Renew", mock.Anything, 9).Return(domain.Loan{}, domain.ErrRenewalLimit).Once()
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(echo.POST, "/api/v1/loans/9/renew", nil), rec)
+	c.SetParamNames(ID)
+	c.SetParamValues("9")
+	handler := LoanHandler{LUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.Renew)(c))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+)
+
+const (
+	selectLoan = `SELECT id, copy_id, book_id, member_id, loaned_at, due_at, returned_at, renewals, reminded_at, noticed_at FROM loan`
+
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+type postgresLoanRepository struct {
+	Conn *sql.DB
+	tx   *sql.Tx
+}
+
+func NewPostgresLoanRepository(Conn *sql.DB) domain.LoanRepository {
+	return &postgresLoanRepository{Conn: Conn}
+}
+
+// NewPostgresLoanRepositoryTx returns the loans of tx, read and written in it
+// by a unit of work.
+func NewPostgresLoanRepositoryTx(tx *sql.Tx) domain.LoanRepository {
+	return &postgresLoanRepository{tx: tx}
+}
+
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// db returns where statements run: the transaction of the repository, if any,
+// or Conn.
+func (p *postgresLoanRepository) db() conn {
+	if p.tx != nil {
+		return p.tx
+	}
+	return p.Conn
+}
+
+func (p *postgresLoanRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]domain.Loan, error) {
+	rows, err := p.db().QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make([]domain.Loan, 0)
+	for rows.Next() {
+		loan := domain.Loan{}
+		err = rows.Scan(
+			&loan.ID,
+			&loan.CopyID,
+			&loan.BookID,
+			&loan.MemberID,
+			&loan.LoanedAt,
+			&loan.DueAt,
+			&loan.ReturnedAt,
+			&loan.Renewals,
+			&loan.RemindedAt,
+			&loan.NoticedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, loan)
+	}
+	return result, rows.Err()
+}
+
+func (p *postgresLoanRepository) GetById(ctx context.Context, id int) (domain.Loan, error) {
+	list, err := p.fetch(ctx, selectLoan+` WHERE id = $1`, id)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	if len(list) == 0 {
+		return domain.Loan{}, fmt.Errorf("loan %d: %w", id, domain.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+func (p *postgresLoanRepository) FetchByMember(ctx context.Context, memberID int, num int, offset int) ([]domain.Loan, error) {
+	return p.fetch(ctx, selectLoan+` WHERE member_id = $1 ORDER BY loaned_at DESC, id DESC LIMIT $2 OFFSET $3`, memberID, num, clamp(offset))
+}
+
+func (p *postgresLoanRepository) FetchByBook(ctx context.Context, bookID int, num int, offset int) ([]domain.Loan, error) {
+	return p.fetch(ctx, selectLoan+` WHERE book_id = $1 ORDER BY loaned_at DESC, id DESC LIMIT $2 OFFSET $3`, bookID, num, clamp(offset))
+}
+
+// clamp returns the offset Postgres accepts for offset, which skips nothing
+// when negative.
+func clamp(offset int) int {
+	if offset < 0 {
+		return 0
+	}
+	return offset
+}
+
+// FetchDueBefore returns the active loans due before the given time, the most
+// overdue first.
+func (p *postgresLoanRepository) FetchDueBefore(ctx context.Context, before time.Time) ([]domain.Loan, error) {
+	return p.fetch(ctx, selectLoan+` WHERE returned_at IS NULL AND due_at < $1 ORDER BY due_at, id`, before)
+}
+
+func (p *postgresLoanRepository) MarkReminded(ctx context.Context, id int, at time.Time) error {
+	_, err := p.db().ExecContext(ctx, `UPDATE loan SET reminded_at = $1 WHERE id = $2`, at, id)
+	return err
+}
+
+func (p *postgresLoanRepository) MarkNoticed(ctx context.Context, id int, at time.Time) error {
+	_, err := p.db().ExecContext(ctx, `UPDATE loan SET noticed_at = $1 WHERE id = $2`, at, id)
+	return err
+}
+
+// Add checks the copy out in a single transaction: the copy row is locked so
+// concurrent checkouts of the same copy are serialized, and the partial unique
+// index on active loans guards against anything bypassing this method. A copy
+// on hold fulfills the ready hold of the member.
+func (p *postgresLoanRepository) Add(ctx context.Context, loan *domain.Loan) (err error) {
+	tx, err := p.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx, &err)
+
+	var status domain.CopyStatus
+	err = tx.QueryRowContext(ctx, `SELECT book_id, status FROM copy WHERE id = $1 FOR UPDATE`, loan.CopyID).Scan(&loan.BookID, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("copy %d: %w", loan.CopyID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	switch status {
+	case domain.CopyAvailable:
+	case domain.CopyOnHold:
+		// only the member the copy is set aside for can check it out
+		var res sql.Result
+		res, err = tx.ExecContext(ctx, `UPDATE hold SET status = $1 WHERE copy_id = $2 AND member_id = $3 AND status = $4`,
+			domain.HoldFulfilled, loan.CopyID, loan.MemberID, domain.HoldReady)
+		if err != nil {
+			return err
+		}
+		var rowsAffected int64
+		if rowsAffected, err = res.RowsAffected(); err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("copy %d is held for another member: %w", loan.CopyID, domain.ErrNotAvailable)
+		}
+	default:
+		return fmt.Errorf("copy %d is %s: %w", loan.CopyID, status, domain.ErrNotAvailable)
+	}
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO loan (copy_id, book_id, member_id, loaned_at, due_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		loan.CopyID, loan.BookID, loan.MemberID, loan.LoanedAt, loan.DueAt).Scan(&loan.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return fmt.Errorf("copy %d: %w", loan.CopyID, domain.ErrNotAvailable)
+		case foreignKeyViolation:
+			return fmt.Errorf("member %d: %w", loan.MemberID, domain.ErrNotFound)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE copy SET status = $1, updated_at = now() WHERE id = $2`, domain.CopyOnLoan, loan.CopyID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Return closes the loan and makes its copy available again in a single
+// transaction.
+func (p *postgresLoanRepository) Return(ctx context.Context, loan *domain.Loan) (err error) {
+	tx, err := p.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx, &err)
+
+	res, err := tx.ExecContext(ctx, `UPDATE loan SET returned_at = $1 WHERE id = $2 AND returned_at IS NULL`, loan.ReturnedAt, loan.ID)
+	if err != nil {
+		return err
+	}
+	if err = checkActive(res, loan.ID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE copy SET status = $1, updated_at = now() WHERE id = $2 AND status = $3`,
+		domain.CopyAvailable, loan.CopyID, domain.CopyOnLoan)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Renew stores the new due date of loan, provided nobody renewed or returned
+// it since it was read. The reminders start over for the new due date.
+func (p *postgresLoanRepository) Renew(ctx context.Context, loan *domain.Loan) error {
+	res, err := p.db().ExecContext(ctx, `UPDATE loan SET due_at = $1, renewals = $2, reminded_at = NULL, noticed_at = NULL `+
+		`WHERE id = $3 AND returned_at IS NULL AND renewals = $4`,
+		loan.DueAt, loan.Renewals, loan.ID, loan.Renewals-1)
+	if err != nil {
+		return err
+	}
+	return checkActive(res, loan.ID)
+}
+
+func checkActive(res sql.Result, id int) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("loan %d was returned or renewed meanwhile: %w", id, domain.ErrConflict)
+	}
+	return nil
+}
+
+// txn is the transaction a method runs in: its own one, or the one of the unit
+// of work the repository is bound to, which only the unit of work ends.
+type txn struct {
+	*sql.Tx
+	bound bool
+}
+
+func (p *postgresLoanRepository) begin(ctx context.Context) (*txn, error) {
+	if p.tx != nil {
+		return &txn{Tx: p.tx, bound: true}, nil
+	}
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &txn{Tx: tx}, nil
+}
+
+func (tx *txn) Commit() error {
+	if tx.bound {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+// rollback rolls tx back when the function owning it fails with *err.
+func rollback(tx *txn, err *error) {
+	if *err == nil || tx.bound {
+		return
+	}
+	if errRollback := tx.Rollback(); errRollback != nil {
+		logrus.Error(errRollback)
+	}
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+)
+
+func TestAdd(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	loan := &domain.Loan{CopyID: 5, MemberID: 3, LoanedAt: now, DueAt: now.Add(time.Hour)}
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT book_id, status FROM copy WHERE id = $1 FOR UPDATE`)).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "status"}).AddRow(1, "available"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO loan`)).WithArgs(5, 1, 3, loan.LoanedAt, loan.DueAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE copy SET status = $1`)).WithArgs(domain.CopyOnLoan, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	require.NoError(t, NewPostgresLoanRepository(db).Add(context.TODO(), loan))
+	assert.Equal(t, 9, loan.ID)
+	assert.Equal(t, 1, loan.BookID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddCopyOnLoan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT book_id, status FROM copy`)).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "status"}).AddRow(1, "on_loan"))
+	mock.ExpectRollback()
+
+	err = NewPostgresLoanRepository(db).Add(context.TODO(), &domain.Loan{CopyID: 5, MemberID: 3})
+	assert.ErrorIs(t, err, domain.ErrNotAvailable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRenewConcurrently(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	due := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE loan SET due_at = $1, renewals = $2, reminded_at = NULL, noticed_at = NULL WHERE id = $3 AND returned_at IS NULL AND renewals = $4`)).
+		WithArgs(due, 2, 9, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = NewPostgresLoanRepository(db).Renew(context.TODO(), &domain.Loan{ID: 9, DueAt: due, Renewals: 2})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestReturn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	returned := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE loan SET returned_at = $1`)).WithArgs(&returned, 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE copy SET status = $1`)).WithArgs(domain.CopyAvailable, 5, domain.CopyOnLoan).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = NewPostgresLoanRepository(db).Return(context.TODO(), &domain.Loan{ID: 9, CopyID: 5, ReturnedAt: &returned})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchByMemberNegativeOffset(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE member_id = $1`)).WithArgs(3, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "copy_id", "book_id", "member_id", "loaned_at", "due_at", "returned_at", "renewals", "reminded_at", "noticed_at"}))
+
+	loans, err := NewPostgresLoanRepository(db).FetchByMember(context.TODO(), 3, 10, -5)
+
+	require.NoError(t, err)
+	assert.Empty(t, loans)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// authorizedLoanUseCase checks the permission of the caller before every
+// operation of the wrapped use case.
+type authorizedLoanUseCase struct {
+	next       domain.LoanUseCase
+	authorizer domain.Authorizer
+}
+
+func NewAuthorizedLoanUseCase(next domain.LoanUseCase, authorizer domain.Authorizer) domain.LoanUseCase {
+	return &authorizedLoanUseCase{next: next, authorizer: authorizer}
+}
+
+func (a *authorizedLoanUseCase) Checkout(ctx context.Context, loan *domain.Loan) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteLoans); err != nil {
+		return err
+	}
+	return a.next.Checkout(ctx, loan)
+}
+
+func (a *authorizedLoanUseCase) Return(ctx context.Context, id int) (domain.Loan, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteLoans); err != nil {
+		return domain.Loan{}, err
+	}
+	return a.next.Return(ctx, id)
+}
+
+func (a *authorizedLoanUseCase) Renew(ctx context.Context, id int) (domain.Loan, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteLoans); err != nil {
+		return domain.Loan{}, err
+	}
+	return a.next.Renew(ctx, id)
+}
+
+func (a *authorizedLoanUseCase) GetById(ctx context.Context, id int) (domain.Loan, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadLoans); err != nil {
+		return domain.Loan{}, err
+	}
+	return a.next.GetById(ctx, id)
+}
+
+func (a *authorizedLoanUseCase) FetchByMember(ctx context.Context, memberID int, num int, offset int) ([]domain.Loan, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadLoans); err != nil {
+		return nil, err
+	}
+	return a.next.FetchByMember(ctx, memberID, num, offset)
+}
+
+func (a *authorizedLoanUseCase) FetchByBook(ctx context.Context, bookID int, num int, offset int) ([]domain.Loan, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadLoans); err != nil {
+		return nil, err
+	}
+	return a.next.FetchByBook(ctx, bookID, num, offset)
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/i18n"
+	"github.com/bxcodec/library/internal/timeout"
+	mb "github.com/bxcodec/library/message_broker"
+)
+
+// Policy holds the circulation rules applied to every loan
+type Policy struct {
+	// Period is the time a copy may be kept after checkout.
+	Period time.Duration
+	// RenewalPeriod is the time a copy may be kept after a renewal.
+	RenewalPeriod time.Duration
+	// MaxRenewals limits how many times a loan can be renewed.
+	MaxRenewals int
+	// MaxFines blocks checkouts of members owing at least this much, zero
+	// means fines never block checkouts.
+	MaxFines domain.Money
+}
+
+type loanUseCase struct {
+	loanRepo      domain.LoanRepository
+	memberRepo    domain.MemberRepository
+	holds         domain.HoldUseCase
+	fines         domain.FineUseCase
+	unitOfWork    domain.UnitOfWork
+	messageBroker mb.MessageBroker
+	policy        Policy
+	timeouts      timeout.Timeouts
+	now           func() time.Time
+}
+
+func NewLoanUseCase(l domain.LoanRepository, m domain.MemberRepository, h domain.HoldUseCase, f domain.FineUseCase, uow domain.UnitOfWork,
+	mb mb.MessageBroker, policy Policy, timeouts timeout.Timeouts) domain.LoanUseCase {
+	return &loanUseCase{
+		loanRepo:      l,
+		memberRepo:    m,
+		holds:         h,
+		fines:         f,
+		unitOfWork:    uow,
+		messageBroker: mb,
+		policy:        policy,
+		timeouts:      timeouts,
+		now:           time.Now,
+	}
+}
+
+// Checkout loans the copy to the member, who must hold an active membership
+// and may not owe more fines than the policy allows.
+func (l *loanUseCase) Checkout(c context.Context, loan *domain.Loan) error {
+	ctxt, cancel := context.WithTimeout(domain.NewContextWithPrimary(c), l.timeouts.Of("checkout"))
+	defer cancel()
+
+	now := l.now().UTC()
+	member, err := l.memberRepo.GetById(ctxt, loan.MemberID)
+	if err != nil {
+		return err
+	}
+	if member.Status != domain.MemberActive || (member.ExpiresAt != nil && now.After(*member.ExpiresAt)) {
+		return fmt.Errorf("member %d: %w", member.ID, domain.ErrMembershipInactive)
+	}
+	if l.policy.MaxFines > 0 {
+		balance, err := l.fines.Balance(ctxt, member.ID)
+		if err != nil {
+			return err
+		}
+		if balance.Due >= l.policy.MaxFines {
+			return fmt.Errorf("member %d owes %s: %w", member.ID, balance.Due, domain.ErrFinesOutstanding)
+		}
+	}
+
+	loan.ID = 0
+	loan.LoanedAt = now
+	loan.DueAt = now.Add(l.policy.Period)
+	loan.ReturnedAt = nil
+	loan.Renewals = 0
+	if err = l.loanRepo.Add(ctxt, loan); err != nil {
+		return err
+	}
+	l.publishEvent(c, mb.CHECKOUT, *loan)
+	return nil
+}
+
+// Return closes the loan, charges the fine of a late return and sets the copy
+// aside for the next hold of its book, all in one unit of work.
+func (l *loanUseCase) Return(c context.Context, id int) (domain.Loan, error) {
+	ctxt, cancel := context.WithTimeout(c, l.timeouts.Of("return"))
+	defer cancel()
+
+	loan, err := l.loanRepo.GetById(ctxt, id)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	if !loan.Active() {
+		return domain.Loan{}, fmt.Errorf("loan %d is already returned: %w", id, domain.ErrConflict)
+	}
+
+	now := l.now().UTC()
+	loan.ReturnedAt = &now
+	err = l.unitOfWork.Do(domain.NewContextWithPrimary(ctxt), func(ctx context.Context, repos domain.Repositories) error {
+		if err := repos.Loans.Return(ctx, &loan); err != nil {
+			return err
+		}
+		if _, err := l.fines.AssessIn(ctx, repos, loan); err != nil {
+			return err
+		}
+		return l.holds.AssignIn(ctx, repos, loan.CopyID)
+	})
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	l.publishEvent(c, mb.RETURN, loan)
+	return loan, nil
+}
+
+// Renew extends the due date by the renewal period counted from now, it never
+// moves the due date backwards.
+func (l *loanUseCase) Renew(c context.Context, id int) (domain.Loan, error) {
+	ctxt, cancel := context.WithTimeout(c, l.timeouts.Of("renew"))
+	defer cancel()
+
+	loan, err := l.loanRepo.GetById(ctxt, id)
+	if err != nil {
+		return domain.Loan{}, err
+	}
+	if !loan.Active() {
+		return domain.Loan{}, fmt.Errorf("loan %d is already returned: %w", id, domain.ErrConflict)
+	}
+	if loan.Renewals >= l.policy.MaxRenewals {
+		return domain.Loan{}, fmt.Errorf("loan %d renewed %d times: %w", id, loan.Renewals, domain.ErrRenewalLimit)
+	}
+
+	if due := l.now().UTC().Add(l.policy.RenewalPeriod); due.After(loan.DueAt) {
+		loan.DueAt = due
+	}
+	loan.Renewals++
+	loan.RemindedAt = nil
+	loan.NoticedAt = nil
+	if err = l.loanRepo.Renew(ctxt, &loan); err != nil {
+		return domain.Loan{}, err
+	}
+	l.publishEvent(c, mb.RENEW, loan)
+	return loan, nil
+}
+
+func (l *loanUseCase) GetById(c context.Context, id int) (domain.Loan, error) {
+	ctxt, cancel := context.WithTimeout(c, l.timeouts.Of("get_by_id"))
+	defer cancel()
+
+	return l.loanRepo.GetById(ctxt, id)
+}
+
+func (l *loanUseCase) FetchByMember(c context.Context, memberID int, num int, offset int) ([]domain.Loan, error) {
+	if num == 0 {
+		num = 10
+	}
+	ctxt, cancel := context.WithTimeout(c, l.timeouts.Of("fetch_by_member"))
+	defer cancel()
+
+	return l.loanRepo.FetchByMember(ctxt, memberID, num, offset)
+}
+
+func (l *loanUseCase) FetchByBook(c context.Context, bookID int, num int, offset int) ([]domain.Loan, error) {
+	if num == 0 {
+		num = 10
+	}
+	ctxt, cancel := context.WithTimeout(c, l.timeouts.Of("fetch_by_book"))
+	defer cancel()
+
+	return l.loanRepo.FetchByBook(ctxt, bookID, num, offset)
+}
+
+// publishEvent sends the loan as the content of an event of eventType. The
+// loan is already stored, so a failure is only logged.
+func (l *loanUseCase) publishEvent(ctx context.Context, eventType mb.EventType, loan domain.Loan) {
+	content, err := json.Marshal(loan)
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+	event := mb.Event{Content: string(content), Subject: string(eventType), Locale: i18n.Locale(ctx)}
+	if principal, ok := domain.PrincipalFromContext(ctx); ok {
+		event.Actor = principal.Subject
+	}
+	event.Tenant, _ = domain.TenantFromContext(ctx)
+	if err = l.messageBroker.Send(event); err != nil {
+		log.Println(err.Error())
+	}
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	mb "github.com/bxcodec/library/message_broker"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/transaction"
+)
+
+var testPolicy = Policy{Period: 14 * 24 * time.Hour, RenewalPeriod: 7 * 24 * time.Hour, MaxRenewals: 2}
+
+func newTestUseCase(loanRepo *mocks.LoanRepository, memberRepo *mocks.MemberRepository, broker *mocks.MessageBroker, now time.Time) *loanUseCase {
+	l := NewLoanUseCase(loanRepo, memberRepo, new(mocks.HoldUseCase), new(mocks.FineUseCase), transaction.None(domain.Repositories{Loans: loanRepo}),
+		broker, testPolicy, timeout.Every(time.Second)).(*loanUseCase)
+	l.now = func() time.Time { return now }
+	return l
+}
+
+func TestCheckout(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	mockLoanRepo := new(mocks.LoanRepository)
+	mockMemberRepo := new(mocks.MemberRepository)
+	mockBroker := new(mocks.MessageBroker)
+
+	mockMemberRepo.On("GetById", mock.Anything, 3).Return(domain.Member{ID: 3, Status: domain.MemberActive}, nil).Once()
+	mockLoanRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.Loan")).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Loan).ID = 9 }).
+		Return(nil).Once()
+	mockBroker.On("Send", mock.MatchedBy(func(e mb.Event) bool { return e.Subject == string(mb.CHECKOUT) })).Return(nil).Once()
+
+	loan := domain.Loan{CopyID: 5, MemberID: 3}
+	err := newTestUseCase(mockLoanRepo, mockMemberRepo, mockBroker, now).Checkout(context.TODO(), &loan)
+
+	require.NoError(t, err)
+	assert.Equal(t, 9, loan.ID)
+	assert.Equal(t, now, loan.LoanedAt)
+	assert.Equal(t, now.Add(testPolicy.Period), loan.DueAt)
+	mockLoanRepo.AssertExpectations(t)
+	mockBroker.AssertExpectations(t)
+}
+
+func TestCheckoutInactiveMember(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	tests := map[string]domain.Member{
+		"suspended": {ID: 3, Status: domain.MemberSuspended},
+		"expired":   {ID: 3, Status: domain.MemberActive, ExpiresAt: &expired},
+	}
+	for name, member := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockLoanRepo := new(mocks.LoanRepository)
+			mockMemberRepo := new(mocks.MemberRepository)
+			mockMemberRepo.On("GetById", mock.Anything, 3).Return(member, nil).Once()
+
+			err := newTestUseCase(mockLoanRepo, mockMemberRepo, new(mocks.MessageBroker), now).
+				Checkout(context.TODO(), &domain.Loan{CopyID: 5, MemberID: 3})
+
+			assert.ErrorIs(t, err, domain.ErrMembershipInactive)
+			mockLoanRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReturnTwice(t *testing.T) {
+	returned := time.Now()
+	mockLoanRepo := new(mocks.LoanRepository)
+	mockLoanRepo.On("GetById", mock.Anything, 9).Return(domain.Loan{ID: 9, ReturnedAt: &returned}, nil).Once()
+
+	_, err := newTestUseCase(mockLoanRepo, new(mocks.MemberRepository), new(mocks.MessageBroker), time.Now()).Return(context.TODO(), 9)
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	mockLoanRepo.AssertNotCalled(t, "Return", mock.Anything, mock.Anything)
+}
+
+func TestRenew(t *testing.T) {
+	now := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+	mockLoanRepo := new(mocks.LoanRepository)
+	mockBroker := new(mocks.MessageBroker)
+	mockLoanRepo.On("GetById", mock.Anything, 9).Return(domain.Loan{ID: 9, DueAt: due, Renewals: 1}, nil).Once()
+	mockLoanRepo.On("Renew", mock.Anything, mock.AnythingOfType("*domain.Loan")).Return(nil).Once()
+	mockBroker.On("Send", mock.Anything).Return(nil).Once()
+
+	loan, err := newTestUseCase(mockLoanRepo, new(mocks.MemberRepository), mockBroker, now).Renew(context.TODO(), 9)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, loan.Renewals)
+	assert.Equal(t, now.Add(testPolicy.RenewalPeriod), loan.DueAt)
+	mockLoanRepo.AssertExpectations(t)
+}
+
+func TestRenewLimit(t *testing.T) {
+	mockLoanRepo := new(mocks.LoanRepository)
+	mockLoanRepo.On("GetById", mock.Anything, 9).Return(domain.Loan{ID: 9, Renewals: testPolicy.MaxRenewals}, nil).Once()
+
+	_, err := newTestUseCase(mockLoanRepo, new(mocks.MemberRepository), new(mocks.MessageBroker), time.Now()).Renew(context.TODO(), 9)
+
+	assert.ErrorIs(t, err, domain.ErrRenewalLimit)
+	mockLoanRepo.AssertNotCalled(t, "Renew", mock.Anything, mock.Anything)
+}
+
+func TestReturnAssignsHold(t *testing.T) {
+	now := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	mockLoanRepo := new(mocks.LoanRepository)
+	mockHolds := new(mocks.HoldUseCase)
+	mockBroker := new(mocks.MessageBroker)
+	mockLoanRepo.On("GetById", mock.Anything, 9).Return(domain.Loan{ID: 9, CopyID: 5}, nil).Once()
+	mockLoanRepo.On("Return", mock.Anything, mock.AnythingOfType("*domain.Loan")).Return(nil).Once()
+	mockBroker.On("Send", mock.Anything).Return(nil).Once()
+	mockHolds.On("AssignIn", mock.Anything, mock.Anything, 5).Return(nil).Once()
+	mockFines := new(mocks.FineUseCase)
+	mockFines.On("AssessIn", mock.Anything, mock.Anything, mock.MatchedBy(func(loan domain.Loan) bool { return loan.ReturnedAt != nil })).
+		Return(domain.Fine{}, nil).Once()
+
+	l := newTestUseCase(mockLoanRepo, new(mocks.MemberRepository), mockBroker, now)
+	l.holds = mockHolds
+	l.fines = mockFines
+	loan, err := l.Return(context.TODO(), 9)
+
+	require.NoError(t, err)
+	assert.Equal(t, now, *loan.ReturnedAt)
+	mockHolds.AssertExpectations(t)
+	mockFines.AssertExpectations(t)
+}
+
+func TestReturnFailsWithItsFine(t *testing.T) {
+	mockLoanRepo := new(mocks.LoanRepository)
+	mockHolds := new(mocks.HoldUseCase)
+	mockBroker := new(mocks.MessageBroker)
+	mockLoanRepo.On("GetById", mock.Anything, 9).Return(domain.Loan{ID: 9, CopyID: 5}, nil).Once()
+	mockLoanRepo.On("Return", mock.Anything, mock.AnythingOfType("*domain.Loan")).Return(nil).Once()
+	mockFines := new(mocks.FineUseCase)
+	mockFines.On("AssessIn", mock.Anything, mock.Anything, mock.Anything).Return(domain.Fine{}, errors.New("unexpected")).Once()
+
+	l := newTestUseCase(mockLoanRepo, new(mocks.MemberRepository), mockBroker, time.Now())
+	l.holds = mockHolds
+	l.fines = mockFines
+	_, err := l.Return(context.TODO(), 9)
+
+	assert.Error(t, err)
+	mockHolds.AssertNotCalled(t, "AssignIn", mock.Anything, mock.Anything, mock.Anything)
+	mockBroker.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestCheckoutFinesOutstanding(t *testing.T) {
+	mockLoanRepo := new(mocks.LoanRepository)
+	mockMemberRepo := new(mocks.MemberRepository)
+	mockFines := new(mocks.FineUseCase)
+	mockMemberRepo.On("GetById", mock.Anything, 3).Return(domain.Member{ID: 3, Status: domain.MemberActive}, nil).Once()
+	mockFines.On("Balance", mock.Anything, 3).Return(domain.Balance{Fines: 700, Payments: 200, Due: 500}, nil).Once()
+
+	l := newTestUseCase(mockLoanRepo, mockMemberRepo, new(mocks.MessageBroker), time.Now())
+	l.fines = mockFines
+	l.policy.MaxFines = 500
+	err := l.Checkout(context.TODO(), &domain.Loan{CopyID: 5, MemberID: 3})
+
+	assert.ErrorIs(t, err, domain.ErrFinesOutstanding)
+	mockLoanRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
+
+func TestOverdueJob(t *testing.T) {
+	now := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	reminded := now.Add(-time.Hour)
+	dueSoon := domain.Loan{ID: 1, BookID: 2, MemberID: 3, DueAt: now.Add(time.Hour)}
+	overdue := domain.Loan{ID: 4, BookID: 2, MemberID: 3, DueAt: now.Add(-72 * time.Hour)}
+	noticed := domain.Loan{ID: 5, BookID: 2, MemberID: 3, DueAt: now.Add(-72 * time.Hour), RemindedAt: &reminded, NoticedAt: &reminded}
+	inGrace := domain.Loan{ID: 6, BookID: 2, MemberID: 3, DueAt: now.Add(-time.Hour), RemindedAt: &reminded}
+
+	mockLoanRepo := new(mocks.LoanRepository)
+	mockMemberRepo := new(mocks.MemberRepository)
+	mockBookRepo := new(mocks.BookRepository)
+	mockFines := new(mocks.FineUseCase)
+	mockMail := new(mocks.MailService)
+	mockLoanRepo.On("FetchDueBefore", mock.Anything, now.Add(48*time.Hour)).Return([]domain.Loan{dueSoon, overdue, noticed, inGrace}, nil).Once()
+	mockMemberRepo.On("GetById", mock.Anything, 3).Return(domain.Member{ID: 3, Email: "reader@example.com"}, nil)
+	mockBookRepo.On("GetById", mock.Anything, 2).Return(domain.Book{ID: 2, Title: "Dune"}, nil)
+	mockFines.On("Assess", mock.Anything, overdue).Return(domain.Fine{LoanID: 4, Amount: 75}, nil).Once()
+	mockFines.On("Assess", mock.Anything, noticed).Return(domain.Fine{LoanID: 5, Amount: 75}, nil).Once()
+	mockFines.On("Assess", mock.Anything, inGrace).Return(domain.Fine{LoanID: 6}, nil).Once()
+	mockMail.On("SendEmail", mock.MatchedBy(func(e mb.Event) bool {
+		return e.Subject == string(mb.LOAN_DUE_SOON) && e.To == "reader@example.com"
+	})).Return(nil).Once()
+	mockMail.On("SendEmail", mock.MatchedBy(func(e mb.Event) bool {
+		return e.Subject == string(mb.LOAN_OVERDUE) && strings.Contains(e.Content, "0.75")
+	})).Return(nil).Once()
+	mockLoanRepo.On("MarkReminded", mock.Anything, 1, now).Return(nil).Once()
+	mockLoanRepo.On("MarkNoticed", mock.Anything, 4, now).Return(nil).Once()
+
+	job := NewOverdueJob(mockLoanRepo, mockMemberRepo, mockBookRepo, mockFines, mockMail, 48*time.Hour, 24*time.Hour, timeout.Every(time.Second))
+	job.now = func() time.Time { return now }
+
+	require.NoError(t, job.Run(context.TODO()))
+	mockLoanRepo.AssertExpectations(t)
+	mockFines.AssertExpectations(t)
+	mockMail.AssertExpectations(t)
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/i18n"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/mail"
+	mb "github.com/bxcodec/library/message_broker"
+)
+
+const dueFormat = "2006-01-02 15:04 MST"
+
+// OverdueJob reminds members of loans that are due soon, charges the fines of
+// overdue loans and notifies the members once a loan is charged a fine or its
+// grace period is over.
+type OverdueJob struct {
+	loanRepo     domain.LoanRepository
+	memberRepo   domain.MemberRepository
+	bookRepo     domain.BookRepository
+	fines        domain.FineUseCase
+	mailService  mail.Sender
+	remindBefore time.Duration
+	grace        time.Duration
+	timeouts     timeout.Timeouts
+	now          func() time.Time
+}
+
+func NewOverdueJob(l domain.LoanRepository, m domain.MemberRepository, b domain.BookRepository, f domain.FineUseCase, mail mail.Sender, remindBefore time.Duration, grace time.Duration, timeouts timeout.Timeouts) *OverdueJob {
+	return &OverdueJob{
+		loanRepo:     l,
+		memberRepo:   m,
+		bookRepo:     b,
+		fines:        f,
+		mailService:  mail,
+		remindBefore: remindBefore,
+		grace:        grace,
+		timeouts:     timeouts,
+		now:          time.Now,
+	}
+}
+
+// Run processes every open loan due before the reminder window ends. A loan is
+// reminded and noticed at most once, renewing it allows both again.
+func (o *OverdueJob) Run(c context.Context) error {
+	now := o.now().UTC()
+	ctxt, cancel := context.WithTimeout(c, o.timeouts.Of("overdue"))
+	loans, err := o.loanRepo.FetchDueBefore(ctxt, now.Add(o.remindBefore))
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for _, loan := range loans {
+		if !loan.DueAt.Before(now) {
+			if loan.RemindedAt == nil {
+				o.remind(c, loan, now)
+			}
+			continue
+		}
+		fine, err := o.fines.Assess(c, loan)
+		if err != nil {
+			log.Println(err.Error())
+			continue
+		}
+		// Within the grace period the notice would show no fine, and it is
+		// only sent once.
+		if loan.NoticedAt == nil && (fine.Amount > 0 || !now.Before(loan.DueAt.Add(o.grace))) {
+			o.notice(c, loan, fine, now)
+		}
+	}
+	return nil
+}
+
+func (o *OverdueJob) remind(c context.Context, loan domain.Loan, now time.Time) {
+	locale := i18n.Locale(c)
+	if !o.send(c, loan, mb.LOAN_DUE_SOON, func(title string) string {
+		return i18n.T(locale, i18n.LoanDueSoon, title, loan.DueAt.Format(dueFormat))
+	}) {
+		return
+	}
+
+	ctxt, cancel := context.WithTimeout(c, o.timeouts.Of("overdue"))
+	defer cancel()
+	if err := o.loanRepo.MarkReminded(ctxt, loan.ID, now); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+func (o *OverdueJob) notice(c context.Context, loan domain.Loan, fine domain.Fine, now time.Time) {
+	locale := i18n.Locale(c)
+	if !o.send(c, loan, mb.LOAN_OVERDUE, func(title string) string {
+		return i18n.T(locale, i18n.LoanOverdue, title, loan.DueAt.Format(dueFormat), fine.Amount.String())
+	}) {
+		return
+	}
+
+	ctxt, cancel := context.WithTimeout(c, o.timeouts.Of("overdue"))
+	defer cancel()
+	if err := o.loanRepo.MarkNoticed(ctxt, loan.ID, now); err != nil {
+		log.Println(err.Error())
+	}
+}
+
+// send emails the member of the loan the content built from the book title and
+// tells whether the email was sent.
+func (o *OverdueJob) send(c context.Context, loan domain.Loan, eventType mb.EventType, content func(title string) string) bool {
+	ctxt, cancel := context.WithTimeout(c, o.timeouts.Of("overdue"))
+	defer cancel()
+
+	member, err := o.memberRepo.GetById(ctxt, loan.MemberID)
+	if err != nil {
+		log.Println(err.Error())
+		return false
+	}
+	book, err := o.bookRepo.GetById(ctxt, loan.BookID)
+	if err != nil {
+		log.Println(err.Error())
+		return false
+	}
+
+	event := mb.Event{Subject: string(eventType), Content: content(book.Title), Locale: i18n.Locale(c), To: member.Email}
+	event.Tenant, _ = domain.TenantFromContext(c)
+	if err = o.mailService.SendEmail(event); err != nil {
+		log.Println(err.Error())
+		return false
+	}
+	return true
+}
+
+// RunOverdue runs the job every interval until ctx is done.
+func RunOverdue(ctx context.Context, job *OverdueJob, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job.Run(ctx); err != nil {
+				log.Println(err.Error())
+			}
+		}
+	}
+}
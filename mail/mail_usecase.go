@@ -4,36 +4,71 @@ import (
 	"fmt"
 	"github.com/bxcodec/library/message_broker"
 	"github.com/spf13/viper"
+	"mime"
 	"net/smtp"
 )
 
 type emailUseCase struct {
-	email Email
+	email   Email
+	tenants map[string]Email
 }
 
-func init() {
-	viper.SetConfigFile(`config.json`)
+// NewSender returns a sender using the mail settings, overridden for the
+// events of a tenant by the settings under tenants.list.<id>.mail.
+func NewSender() Sender {
+	e := &emailUseCase{email: settings(`mail`, Email{}), tenants: make(map[string]Email)}
+	for id := range viper.GetStringMap(`tenants.list`) {
+		key := `tenants.list.` + id + `.mail`
+		if viper.IsSet(key) {
+			e.tenants[id] = settings(key, e.email)
+		}
+	}
+	return e
+}
 
-	if err := viper.ReadInConfig(); err != nil {
-		panic(err)
+// settings reads the mail settings under key, falling back to defaults for the
+// missing ones.
+func settings(key string, defaults Email) Email {
+	email := defaults
+	if from := viper.GetString(key + `.from`); from != "" {
+		email.from = from
+	}
+	if password := viper.GetString(key + `.pass`); password != "" {
+		email.password = password
+	}
+	if to := viper.GetString(key + `.to`); to != "" {
+		email.toEmail = []string{to}
+	}
+	if host := viper.GetString(key + `.host`); host != "" {
+		email.host = host
+	}
+	if port := viper.GetString(key + `.port`); port != "" {
+		email.port = port
 	}
+	return email
 }
 
-func NewSender() Sender {
-	return &emailUseCase{email: Email{
-		from:     viper.GetString(`mail.from`),
-		password: viper.GetString(`mail.pass`),
-		toEmail:  []string{viper.GetString(`mail.to`)},
-		host:     viper.GetString(`mail.host`),
-		port:     viper.GetString(`mail.port`),
-	}}
+// settingsFor returns the settings the events of the tenant are sent with.
+func (e emailUseCase) settingsFor(tenantID string) Email {
+	if email, ok := e.tenants[tenantID]; ok {
+		return email
+	}
+	return e.email
 }
 
 func (e emailUseCase) SendEmail(event message_broker.Event) error {
-	address := e.email.host + ":" + e.email.port
-	auth := smtp.PlainAuth("", e.email.from, e.email.password, e.email.host)
-	email := email{event}.compress()
-	err := smtp.SendMail(address, auth, e.email.from, e.email.toEmail, email)
+	settings := e.settingsFor(event.Tenant)
+	address := settings.host + ":" + settings.port
+	auth := smtp.PlainAuth("", settings.from, settings.password, settings.host)
+	email, err := email{event}.compress()
+	if err != nil {
+		return err
+	}
+	to := settings.toEmail
+	if event.To != "" {
+		to = []string{event.To}
+	}
+	err = smtp.SendMail(address, auth, settings.from, to, email)
 	if err != nil {
 		err = fmt.Errorf("Error while sending email with %s subject. ", event.Subject)
 	}
@@ -44,8 +79,12 @@ type email struct {
 	event message_broker.Event
 }
 
-func (email email) compress() []byte {
-	emailSubject := fmt.Sprintf("Subject: %s\r\n\r\n", email.event.Subject)
-	emailContent := fmt.Sprintf(" %s\r\n", email.event.Content)
-	return []byte(emailSubject + emailContent)
+func (email email) compress() ([]byte, error) {
+	title, content, err := render(email.event)
+	if err != nil {
+		return nil, err
+	}
+	emailHeaders := "MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n"
+	emailSubject := fmt.Sprintf("Subject: %s\r\n\r\n", mime.QEncoding.Encode("UTF-8", title))
+	return []byte(emailHeaders + emailSubject + content), nil
 }
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"mime"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/i18n"
+	"github.com/bxcodec/library/message_broker"
+)
+
+func TestCompress(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		expected string
+	}{
+		{name: "english", locale: i18n.English, expected: "Subject: Book updated\r\n\r\n World\r\nChanged fields: title, content\r\n"},
+		{name: "russian", locale: i18n.Russian, expected: "Subject: " + mime.QEncoding.Encode("UTF-8", "Книга изменена") + "\r\n\r\n World\r\nИзмененные поля: title, content\r\n"},
+		{name: "missing locale", locale: "de", expected: "Subject: Book updated\r\n\r\n World\r\nChanged fields: title, content\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := message_broker.Event{
+				Content: "World",
+				Subject: string(message_broker.UPDATE),
+				Fields:  []string{"title", "content"},
+				Locale:  tt.locale,
+			}
+			res, err := email{event}.compress()
+			require.NoError(t, err)
+			assert.Equal(t, "MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n"+tt.expected, string(res))
+		})
+	}
+}
+
+func TestSenderTenantSettings(t *testing.T) {
+	viper.Set(`mail.from`, "library@example.com")
+	viper.Set(`mail.host`, "smtp.example.com")
+	viper.Set(`mail.port`, "587")
+	viper.Set(`tenants.list`, map[string]interface{}{
+		"central": map[string]interface{}{},
+		"east":    map[string]interface{}{"mail": map[string]interface{}{"from": "east@example.com"}},
+	})
+	defer viper.Reset()
+
+	sender := NewSender().(*emailUseCase)
+
+	assert.Equal(t, "library@example.com", sender.settingsFor("central").from)
+	assert.Equal(t, "library@example.com", sender.settingsFor("").from)
+	east := sender.settingsFor("east")
+	assert.Equal(t, "east@example.com", east.from)
+	assert.Equal(t, "smtp.example.com", east.host)
+}
@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/bxcodec/library/i18n"
+	mb "github.com/bxcodec/library/message_broker"
+)
+
+// notification is the data every email template is executed with
+type notification struct {
+	Title   string
+	Content string
+	Fields  string
+}
+
+var titles = map[string]map[string]string{
+	i18n.English: {
+		string(mb.GetById): "Book viewed",
+		string(mb.FETCH):   "Book listed",
+		string(mb.ADD):     "Book added",
+		string(mb.UPDATE):  "Book updated",
+		string(mb.DELETE):  "Book deleted",
+
+		string(mb.HOLD_READY):    "Your hold is ready for pickup",
+		string(mb.LOAN_DUE_SOON): "Your loan is due soon",
+		string(mb.LOAN_OVERDUE):  "Your loan is overdue",
+	},
+	i18n.Russian: {
+		string(mb.GetById): "Книга просмотрена",
+		string(mb.FETCH):   "Книга в списке",
+		string(mb.ADD):     "Книга добавлена",
+		string(mb.UPDATE):  "Книга изменена",
+		string(mb.DELETE):  "Книга удалена",
+
+		string(mb.HOLD_READY):    "Забронированная книга ждет вас",
+		string(mb.LOAN_DUE_SOON): "Скоро нужно вернуть книгу",
+		string(mb.LOAN_OVERDUE):  "Книга не возвращена вовремя",
+	},
+}
+
+var templates = map[string]*template.Template{
+	i18n.English: template.Must(template.New(i18n.English).Parse(
+		" {{.Content}}\r\n{{if .Fields}}Changed fields: {{.Fields}}\r\n{{end}}")),
+	i18n.Russian: template.Must(template.New(i18n.Russian).Parse(
+		" {{.Content}}\r\n{{if .Fields}}Измененные поля: {{.Fields}}\r\n{{end}}")),
+}
+
+// render returns the localized title and body of the notification about
+// event, falling back to English for locales without templates.
+func render(event mb.Event) (string, string, error) {
+	locale := event.Locale
+	if _, ok := templates[locale]; !ok {
+		locale = i18n.English
+	}
+
+	title, ok := titles[locale][event.Subject]
+	if !ok {
+		title = event.Subject
+	}
+
+	var body strings.Builder
+	err := templates[locale].Execute(&body, notification{
+		Title:   title,
+		Content: event.Content,
+		Fields:  strings.Join(event.Fields, ", "),
+	})
+	return title, body.String(), err
+}
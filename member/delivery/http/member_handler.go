@@ -0,0 +1,130 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/validation"
+)
+
+const (
+	NUM         = "num"
+	OFFSET      = "offset"
+	ID          = "id"
+	CARD_NUMBER = "card_number"
+)
+
+type MemberHandler struct {
+	MUseCase  domain.MemberUseCase
+	validator *validator.Validate
+}
+
+// NewMemberHandler registers the member routes on e, each of them wrapped in
+// middleware.
+func NewMemberHandler(e *echo.Echo, us domain.MemberUseCase, middleware ...echo.MiddlewareFunc) {
+	handler := &MemberHandler{MUseCase: us, validator: validation.Validator()}
+	handler.Register(e.Group("/api/v1", middleware...))
+}
+
+func (h *MemberHandler) Register(g *echo.Group) {
+	g.GET("/members", h.Fetch)
+	g.GET("/members/:id", h.GetById)
+	g.POST("/members", h.Add)
+	g.PUT("/members/:id", h.Update)
+	g.DELETE("/members/:id", h.Delete)
+}
+
+// Fetch lists the members, or searches them by card number when the
+// card_number query parameter is set.
+func (h MemberHandler) Fetch(c echo.Context) error {
+	if cardNumber := c.QueryParam(CARD_NUMBER); cardNumber != "" {
+		member, err := h.MUseCase.GetByCardNumber(c.Request().Context(), cardNumber)
+		if errors.Is(err, domain.ErrNotFound) {
+			return c.JSON(http.StatusOK, []domain.Member{})
+		}
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, []domain.Member{member})
+	}
+
+	num, _ := strconv.Atoi(c.QueryParam(NUM))
+	offset, _ := strconv.Atoi(c.QueryParam(OFFSET))
+	members, err := h.MUseCase.Fetch(c.Request().Context(), num, offset)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, members)
+}
+
+func (h MemberHandler) GetById(c echo.Context) error {
+	id, err := memberID(c)
+	if err != nil {
+		return err
+	}
+
+	member, err := h.MUseCase.GetById(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, member)
+}
+
+func (h MemberHandler) Add(c echo.Context) error {
+	var member domain.Member
+	if err := c.Bind(&member); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(&member); err != nil {
+		return err
+	}
+
+	if err := h.MUseCase.Add(c.Request().Context(), &member); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, member)
+}
+
+func (h MemberHandler) Update(c echo.Context) error {
+	var member domain.Member
+	var err error
+	if err = c.Bind(&member); err != nil {
+		return err
+	}
+	if member.ID, err = memberID(c); err != nil {
+		return err
+	}
+	if err = h.validator.Struct(&member); err != nil {
+		return err
+	}
+
+	if err = h.MUseCase.Update(c.Request().Context(), &member); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, member)
+}
+
+func (h MemberHandler) Delete(c echo.Context) error {
+	id, err := memberID(c)
+	if err != nil {
+		return err
+	}
+
+	if err = h.MUseCase.Delete(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func memberID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param(ID))
+	if err != nil {
+		return 0, &domain.InvalidIDError{Param: ID, Value: c.Param(ID)}
+	}
+	return id, nil
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/validation"
+)
+
+func TestFetchByCardNumber(t *testing.T) {
+	mockUCase := new(mocks.MemberUseCase)
+	mockUCase.On("GetByCardNumber", mock.Anything, "C-1").Return(domain.Member{ID: 3, CardNumber: "C-1"}, nil).Once()
+	mockUCase.On("GetByCardNumber", mock.Anything, "C-2").Return(domain.Member{}, domain.ErrNotFound).Once()
+	handler := MemberHandler{MUseCase: mockUCase, validator: validation.Validator()}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler.Fetch(e.NewContext(httptest.NewRequest(echo.GET, "/api/v1/members?card_number=C-1", nil), rec)))
+	var members []domain.Member
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &members))
+	assert.Len(t, members, 1)
+
+	rec = httptest.NewRecorder()
+	require.NoError(t, handler.Fetch(e.NewContext(httptest.NewRequest(echo.GET, "/api/v1/members?card_number=C-2", nil), rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[]`, rec.Body.String())
+	mockUCase.AssertExpectations(t)
+}
+
+func TestAddConflict(t *testing.T) {
+	mockUCase := new(mocks.MemberUseCase)
+	mockUCase.On("Add", mock.Anything, mock.AnythingOfType("*domain.Member")).Return(domain.ErrConflict)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/members", strings.NewReader(`{"name":"Ann","email":"ann@example.com","card_number":"C-1","branch_id":1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := MemberHandler{MUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.Add)(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, problem.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+}
+
+func TestAddInvalidEmail(t *testing.T) {
+	mockUCase := new(mocks.MemberUseCase)
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/members", strings.NewReader(`{"name":"Ann","email":"ann","card_number":"C-1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := MemberHandler{MUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.Add)(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"email"`)
+	mockUCase.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
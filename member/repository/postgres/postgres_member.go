@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/replica"
+)
+
+const (
+	selectMember = `SELECT id, name, email, card_number, branch_id, status, expires_at, created_at, updated_at FROM member`
+
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+type postgresMemberRepository struct {
+	Conn *replica.Cluster
+}
+
+// NewPostgresMemberRepository returns the members of Conn, read from its replicas
+// and written to its primary.
+func NewPostgresMemberRepository(Conn *replica.Cluster) domain.MemberRepository {
+	return &postgresMemberRepository{Conn}
+}
+
+func (p *postgresMemberRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]domain.Member, error) {
+	rows, err := p.Conn.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make([]domain.Member, 0)
+	for rows.Next() {
+		member := domain.Member{}
+		err = rows.Scan(
+			&member.ID,
+			&member.Name,
+			&member.Email,
+			&member.CardNumber,
+			&member.BranchID,
+			&member.Status,
+			&member.ExpiresAt,
+			&member.CreatedAt,
+			&member.UpdatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, member)
+	}
+	return result, rows.Err()
+}
+
+func (p *postgresMemberRepository) getOne(ctx context.Context, what string, query string, args ...interface{}) (domain.Member, error) {
+	list, err := p.fetch(ctx, query, args...)
+	if err != nil {
+		return domain.Member{}, err
+	}
+	if len(list) == 0 {
+		return domain.Member{}, fmt.Errorf("member %s: %w", what, domain.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+func (p *postgresMemberRepository) Fetch(ctx context.Context, num int, offset int) ([]domain.Member, error) {
+	return p.fetch(ctx, selectMember+` ORDER BY id LIMIT $1 OFFSET $2`, num, offset)
+}
+
+func (p *postgresMemberRepository) GetById(ctx context.Context, id int) (domain.Member, error) {
+	return p.getOne(ctx, fmt.Sprint(id), selectMember+` WHERE id = $1`, id)
+}
+
+func (p *postgresMemberRepository) GetByCardNumber(ctx context.Context, cardNumber string) (domain.Member, error) {
+	return p.getOne(ctx, fmt.Sprintf("card %q", cardNumber), selectMember+` WHERE card_number = $1`, cardNumber)
+}
+
+func (p *postgresMemberRepository) GetByEmail(ctx context.Context, email string) (domain.Member, error) {
+	return p.getOne(ctx, fmt.Sprintf("email %q", email), selectMember+` WHERE lower(email) = lower($1)`, email)
+}
+
+func (p *postgresMemberRepository) Add(ctx context.Context, m *domain.Member) error {
+	err := p.Conn.Primary().QueryRowContext(ctx, `INSERT INTO member (name, email, card_number, branch_id, status, expires_at) `+
+		`VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`,
+		m.Name, m.Email, m.CardNumber, m.BranchID, m.Status, m.ExpiresAt).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+	return conflict(err)
+}
+
+func (p *postgresMemberRepository) Update(ctx context.Context, m *domain.Member) error {
+	err := p.Conn.Primary().QueryRowContext(ctx, `UPDATE member SET name = $1, email = $2, card_number = $3, branch_id = $4, status = $5, expires_at = $6, updated_at = now() `+
+		`WHERE id = $7 RETURNING created_at, updated_at`,
+		m.Name, m.Email, m.CardNumber, m.BranchID, m.Status, m.ExpiresAt, m.ID).Scan(&m.CreatedAt, &m.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("member %d: %w", m.ID, domain.ErrNotFound)
+	}
+	return conflict(err)
+}
+
+func (p *postgresMemberRepository) Delete(ctx context.Context, id int) error {
+	res, err := p.Conn.Primary().ExecContext(ctx, `DELETE FROM member WHERE id = $1`, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return fmt.Errorf("member %d has loans: %w", id, domain.ErrConflict)
+	}
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("member %d: %w", id, domain.ErrNotFound)
+	}
+	return nil
+}
+
+// conflict reports a violated unique constraint, the email or the card number
+// of another member, as domain.ErrConflict and an unknown branch as
+// domain.ErrNotFound.
+func conflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case uniqueViolation:
+			return fmt.Errorf("member %s: %w", pqErr.Constraint, domain.ErrConflict)
+		case foreignKeyViolation:
+			return fmt.Errorf("member %s: %w", pqErr.Constraint, domain.ErrNotFound)
+		}
+	}
+	return err
+}
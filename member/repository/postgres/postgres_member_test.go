@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/replica"
+)
+
+func TestGetByCardNumber(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{"id", "name", "email", "card_number", "branch_id", "status", "expires_at", "created_at", "updated_at"}).
+		AddRow(3, "Ann", "ann@example.com", "C-1", 1, "active", now, now, now)
+	mock.ExpectQuery(regexp.QuoteMeta(selectMember + ` WHERE card_number = $1`)).WithArgs("C-1").WillReturnRows(rows)
+
+	member, err := NewPostgresMemberRepository(replica.New(db, nil)).GetByCardNumber(context.TODO(), "C-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, member.ID)
+	assert.Equal(t, domain.MemberActive, member.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddDuplicateEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	member := &domain.Member{Name: "Ann", Email: "ann@example.com", CardNumber: "C-1", BranchID: 1, Status: domain.MemberActive}
+	mock.ExpectQuery("INSERT INTO member").
+		WithArgs(member.Name, member.Email, member.CardNumber, member.BranchID, member.Status, member.ExpiresAt).
+		WillReturnError(&pq.Error{Code: uniqueViolation, Constraint: "member_email_key"})
+
+	err = NewPostgresMemberRepository(replica.New(db, nil)).Add(context.TODO(), member)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func TestUpdateNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("UPDATE member").WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}))
+
+	err = NewPostgresMemberRepository(replica.New(db, nil)).Update(context.TODO(), &domain.Member{ID: 3})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
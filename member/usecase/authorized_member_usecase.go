@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// authorizedMemberUseCase checks the permission of the caller before every
+// operation of the wrapped use case.
+type authorizedMemberUseCase struct {
+	next       domain.MemberUseCase
+	authorizer domain.Authorizer
+}
+
+func NewAuthorizedMemberUseCase(next domain.MemberUseCase, authorizer domain.Authorizer) domain.MemberUseCase {
+	return &authorizedMemberUseCase{next: next, authorizer: authorizer}
+}
+
+func (a *authorizedMemberUseCase) Fetch(ctx context.Context, num int, offset int) ([]domain.Member, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadMembers); err != nil {
+		return nil, err
+	}
+	return a.next.Fetch(ctx, num, offset)
+}
+
+func (a *authorizedMemberUseCase) GetById(ctx context.Context, id int) (domain.Member, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadMembers); err != nil {
+		return domain.Member{}, err
+	}
+	return a.next.GetById(ctx, id)
+}
+
+func (a *authorizedMemberUseCase) GetByCardNumber(ctx context.Context, cardNumber string) (domain.Member, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadMembers); err != nil {
+		return domain.Member{}, err
+	}
+	return a.next.GetByCardNumber(ctx, cardNumber)
+}
+
+func (a *authorizedMemberUseCase) Add(ctx context.Context, member *domain.Member) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteMembers); err != nil {
+		return err
+	}
+	return a.next.Add(ctx, member)
+}
+
+func (a *authorizedMemberUseCase) Update(ctx context.Context, member *domain.Member) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteMembers); err != nil {
+		return err
+	}
+	return a.next.Update(ctx, member)
+}
+
+func (a *authorizedMemberUseCase) Delete(ctx context.Context, id int) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteMembers); err != nil {
+		return err
+	}
+	return a.next.Delete(ctx, id)
+}
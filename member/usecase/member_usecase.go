@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/validation"
+)
+
+type memberUseCase struct {
+	memberRepo domain.MemberRepository
+	timeouts   timeout.Timeouts
+	validator  *validator.Validate
+}
+
+func NewMemberUseCase(m domain.MemberRepository, timeouts timeout.Timeouts) domain.MemberUseCase {
+	return &memberUseCase{
+		memberRepo: m,
+		timeouts:   timeouts,
+		validator:  validation.Validator(),
+	}
+}
+
+func (m *memberUseCase) Fetch(c context.Context, num int, offset int) ([]domain.Member, error) {
+	if num == 0 {
+		num = 10
+	}
+	ctxt, cancel := context.WithTimeout(c, m.timeouts.Of("fetch"))
+	defer cancel()
+
+	return m.memberRepo.Fetch(ctxt, num, offset)
+}
+
+func (m *memberUseCase) GetById(c context.Context, id int) (domain.Member, error) {
+	ctxt, cancel := context.WithTimeout(c, m.timeouts.Of("get_by_id"))
+	defer cancel()
+
+	return m.memberRepo.GetById(ctxt, id)
+}
+
+func (m *memberUseCase) GetByCardNumber(c context.Context, cardNumber string) (domain.Member, error) {
+	ctxt, cancel := context.WithTimeout(c, m.timeouts.Of("get_by_card_number"))
+	defer cancel()
+
+	return m.memberRepo.GetByCardNumber(ctxt, strings.TrimSpace(cardNumber))
+}
+
+func (m *memberUseCase) Add(c context.Context, member *domain.Member) error {
+	normalize(member)
+	if member.Status == "" {
+		member.Status = domain.MemberActive
+	}
+	if err := m.validator.Struct(member); err != nil {
+		return err
+	}
+	ctxt, cancel := context.WithTimeout(domain.NewContextWithPrimary(c), m.timeouts.Of("add"))
+	defer cancel()
+
+	if err := m.checkUnique(ctxt, member); err != nil {
+		return err
+	}
+	return m.memberRepo.Add(ctxt, member)
+}
+
+func (m *memberUseCase) Update(c context.Context, member *domain.Member) error {
+	normalize(member)
+	ctxt, cancel := context.WithTimeout(domain.NewContextWithPrimary(c), m.timeouts.Of("update"))
+	defer cancel()
+
+	existing, err := m.memberRepo.GetById(ctxt, member.ID)
+	if err != nil {
+		return err
+	}
+	if member.Status == "" {
+		member.Status = existing.Status
+	}
+	if err = m.validator.Struct(member); err != nil {
+		return err
+	}
+	if err = m.checkUnique(ctxt, member); err != nil {
+		return err
+	}
+	return m.memberRepo.Update(ctxt, member)
+}
+
+func (m *memberUseCase) Delete(c context.Context, id int) error {
+	ctxt, cancel := context.WithTimeout(c, m.timeouts.Of("delete"))
+	defer cancel()
+
+	return m.memberRepo.Delete(ctxt, id)
+}
+
+// checkUnique fails with domain.ErrConflict when another member already uses
+// the email or the card number of member. The database enforces the same
+// constraints, this check only makes the common case cheap and explicit.
+func (m *memberUseCase) checkUnique(ctx context.Context, member *domain.Member) error {
+	other, err := m.memberRepo.GetByEmail(ctx, member.Email)
+	if err == nil && other.ID != member.ID {
+		return fmt.Errorf("member email %q: %w", member.Email, domain.ErrConflict)
+	}
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	other, err = m.memberRepo.GetByCardNumber(ctx, member.CardNumber)
+	if err == nil && other.ID != member.ID {
+		return fmt.Errorf("member card %q: %w", member.CardNumber, domain.ErrConflict)
+	}
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func normalize(member *domain.Member) {
+	member.Name = strings.TrimSpace(member.Name)
+	member.Email = strings.ToLower(strings.TrimSpace(member.Email))
+	member.CardNumber = strings.TrimSpace(member.CardNumber)
+}
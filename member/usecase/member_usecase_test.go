@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	"github.com/bxcodec/library/mocks"
+)
+
+func TestAdd(t *testing.T) {
+	mockRepo := new(mocks.MemberRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "ann@example.com").Return(domain.Member{}, domain.ErrNotFound).Once()
+	mockRepo.On("GetByCardNumber", mock.Anything, "C-1").Return(domain.Member{}, domain.ErrNotFound).Once()
+	mockRepo.On("Add", mock.Anything, mock.AnythingOfType("*domain.Member")).Return(nil).Once()
+
+	member := domain.Member{Name: "Ann", Email: " Ann@Example.com", CardNumber: "C-1", BranchID: 1}
+	err := NewMemberUseCase(mockRepo, timeout.Every(time.Second)).Add(context.TODO(), &member)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ann@example.com", member.Email)
+	assert.Equal(t, domain.MemberActive, member.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAddEmailConflict(t *testing.T) {
+	mockRepo := new(mocks.MemberRepository)
+	mockRepo.On("GetByEmail", mock.Anything, "ann@example.com").Return(domain.Member{ID: 3}, nil).Once()
+
+	member := domain.Member{Name: "Ann", Email: "ann@example.com", CardNumber: "C-1", BranchID: 1}
+	err := NewMemberUseCase(mockRepo, timeout.Every(time.Second)).Add(context.TODO(), &member)
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	mockRepo.AssertNotCalled(t, "Add", mock.Anything, mock.Anything)
+}
+
+func TestUpdateKeepsOwnEmail(t *testing.T) {
+	mockRepo := new(mocks.MemberRepository)
+	existing := domain.Member{ID: 3, Name: "Ann", Email: "ann@example.com", CardNumber: "C-1", Status: domain.MemberSuspended}
+	mockRepo.On("GetById", mock.Anything, 3).Return(existing, nil).Once()
+	mockRepo.On("GetByEmail", mock.Anything, "ann@example.com").Return(existing, nil).Once()
+	mockRepo.On("GetByCardNumber", mock.Anything, "C-2").Return(domain.Member{}, domain.ErrNotFound).Once()
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Member")).Return(nil).Once()
+
+	member := domain.Member{ID: 3, Name: "Ann", Email: "ann@example.com", CardNumber: "C-2", BranchID: 1}
+	err := NewMemberUseCase(mockRepo, timeout.Every(time.Second)).Update(context.TODO(), &member)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.MemberSuspended, member.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthorizedMemberForbidden(t *testing.T) {
+	mockUCase := new(mocks.MemberUseCase)
+	mockAuthorizer := new(mocks.Authorizer)
+	mockAuthorizer.On("Authorize", mock.Anything, domain.PermissionReadMembers).Return(domain.ErrForbidden).Once()
+
+	_, err := NewAuthorizedMemberUseCase(mockUCase, mockAuthorizer).GetByCardNumber(context.TODO(), "C-1")
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	mockUCase.AssertNotCalled(t, "GetByCardNumber", mock.Anything, mock.Anything)
+}
@@ -1,6 +1,7 @@
 package message_broker
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 )
@@ -12,17 +13,53 @@ type MessageBroker interface {
 	Receive(emailChan chan []byte) error
 }
 
+// Subscriber delivers every event whose routing key matches one of patterns to
+// each subscription, unlike Receive whose consumers share the events. It
+// returns when ctx is done or the subscription is lost.
+type Subscriber interface {
+	Subscribe(ctx context.Context, patterns []string, events chan<- Event) error
+}
+
 const (
 	GetById EventType = "get.by.id.sql"
 	UPDATE  EventType = "update.sql"
 	DELETE  EventType = "delete.sql"
 	ADD     EventType = "add.sql"
 	FETCH   EventType = "fetch.sql"
+
+	CHECKOUT EventType = "checkout.loan"
+	RETURN   EventType = "return.loan"
+	RENEW    EventType = "renew.loan"
+
+	HOLD_READY EventType = "ready.hold"
+
+	LOAN_DUE_SOON EventType = "due_soon.loan"
+	LOAN_OVERDUE  EventType = "overdue.loan"
+
+	TRANSFER_REQUESTED EventType = "requested.transfer"
+	TRANSFER_SHIPPED   EventType = "shipped.transfer"
+	TRANSFER_RECEIVED  EventType = "received.transfer"
+	TRANSFER_CANCELLED EventType = "cancelled.transfer"
 )
 
 type Event struct {
-	Content string `json:"content"`
-	Subject string `json:"subject"`
+	Content string   `json:"content"`
+	Subject string   `json:"subject"`
+	Fields  []string `json:"fields,omitempty"`
+	Locale  string   `json:"locale,omitempty"`
+	Actor   string   `json:"actor,omitempty"`
+	To      string   `json:"to,omitempty"`
+	Tenant  string   `json:"tenant,omitempty"`
+	BookID  int      `json:"book_id,omitempty"`
+}
+
+// RoutingKey is the key the event is published with: its subject prefixed with
+// the tenant it belongs to, so that consumers may bind to a single tenant.
+func (e *Event) RoutingKey() string {
+	if e.Tenant == "" {
+		return e.Subject
+	}
+	return e.Tenant + "." + e.Subject
 }
 
 func (e *Event) Marshal() []byte {
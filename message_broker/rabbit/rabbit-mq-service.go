@@ -1,6 +1,8 @@
 package rabbit
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	mb "github.com/bxcodec/library/message_broker"
 	"github.com/spf13/viper"
@@ -24,6 +26,9 @@ func init() {
 	rabbitMqUrl = fmt.Sprintf("amqp://%s:%s@%s:%d/", rabbitUser, rabbitPass, rabbitHost, rabbitPort)
 }
 
+// rabbitMqService publishes the events to the topic exchange named queue with
+// tenant scoped routing keys and consumes them from the queue of the same name
+// bound to every key.
 type rabbitMqService struct {
 	queue string
 }
@@ -32,6 +37,12 @@ func NewRabbitMqService(q string) mb.MessageBroker {
 	return &rabbitMqService{queue: q}
 }
 
+// NewRabbitMqSubscriber subscribes to the events of the exchange named q, each
+// subscription with an exclusive queue of its own.
+func NewRabbitMqSubscriber(q string) mb.Subscriber {
+	return &rabbitMqService{queue: q}
+}
+
 func (r rabbitMqService) Send(event mb.Event) error {
 	conn, err := amqp.Dial(rabbitMqUrl)
 	if err != nil {
@@ -47,24 +58,15 @@ func (r rabbitMqService) Send(event mb.Event) error {
 	}
 	defer ch.Close()
 
-	q, err := ch.QueueDeclare(
-		r.queue, // name
-		false,   // durable
-		false,   // delete when unused
-		false,   // exclusive
-		false,   // no-wait
-		nil,     // arguments
-	)
-	if err != nil {
-		log.Printf("%s: %s", FailedToOpenQueue, err)
+	if _, err = r.declare(ch); err != nil {
 		return err
 	}
 
 	err = ch.Publish(
-		"",     // exchange
-		q.Name, // routing key
-		false,  // mandatory
-		false,  // immediate
+		r.queue,            // exchange
+		event.RoutingKey(), // routing key
+		false,              // mandatory
+		false,              // immediate
 		amqp.Publishing{
 			ContentType: "text/plain",
 			Body:        event.Marshal(),
@@ -92,16 +94,8 @@ func (r rabbitMqService) Receive(emailChan chan []byte) error {
 	}
 	defer ch.Close()
 
-	q, err := ch.QueueDeclare(
-		r.queue, // name
-		false,   // durable
-		false,   // delete when unused
-		false,   // exclusive
-		false,   // no-wait
-		nil,     // arguments
-	)
+	q, err := r.declare(ch)
 	if err != nil {
-		log.Printf("%s: %s", FailedToOpenQueue, err)
 		return err
 	}
 
@@ -125,3 +119,107 @@ func (r rabbitMqService) Receive(emailChan chan []byte) error {
 
 	return nil
 }
+
+func (r rabbitMqService) Subscribe(ctx context.Context, patterns []string, events chan<- mb.Event) error {
+	conn, err := amqp.Dial(rabbitMqUrl)
+	if err != nil {
+		log.Printf("%s: %s", FailedToConnect, err)
+		return err
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		log.Printf("%s: %s", FailedToOpenChannel, err)
+		return err
+	}
+	defer ch.Close()
+
+	if err = r.declareExchange(ch); err != nil {
+		return err
+	}
+	q, err := ch.QueueDeclare(
+		"",    // name
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		log.Printf("%s: %s", FailedToOpenQueue, err)
+		return err
+	}
+	for _, pattern := range patterns {
+		if err = ch.QueueBind(q.Name, pattern, r.queue, false, nil); err != nil {
+			log.Printf("%s: %s", FailedToBindQueue, err)
+			return err
+		}
+	}
+
+	msgs, err := ch.Consume(q.Name, "", true, true, false, false, nil)
+	if err != nil {
+		log.Printf("%s: %s", FailedToRegisterConsumer, err)
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-msgs:
+			if !ok {
+				return errors.New(FailedReceiving)
+			}
+			var event mb.Event
+			select {
+			case events <- *event.Unmarshal(d.Body):
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// declareExchange declares the topic exchange of r.
+func (r rabbitMqService) declareExchange(ch *amqp.Channel) error {
+	err := ch.ExchangeDeclare(
+		r.queue, // name
+		"topic", // type
+		false,   // durable
+		false,   // auto-deleted
+		false,   // internal
+		false,   // no-wait
+		nil,     // arguments
+	)
+	if err != nil {
+		log.Printf("%s: %s", FailedToDeclareExchange, err)
+	}
+	return err
+}
+
+// declare declares the exchange and the queue of r and binds them.
+func (r rabbitMqService) declare(ch *amqp.Channel) (amqp.Queue, error) {
+	if err := r.declareExchange(ch); err != nil {
+		return amqp.Queue{}, err
+	}
+
+	q, err := ch.QueueDeclare(
+		r.queue, // name
+		false,   // durable
+		false,   // delete when unused
+		false,   // exclusive
+		false,   // no-wait
+		nil,     // arguments
+	)
+	if err != nil {
+		log.Printf("%s: %s", FailedToOpenQueue, err)
+		return amqp.Queue{}, err
+	}
+
+	if err = ch.QueueBind(q.Name, "#", r.queue, false, nil); err != nil {
+		log.Printf("%s: %s", FailedToBindQueue, err)
+		return amqp.Queue{}, err
+	}
+	return q, nil
+}
@@ -4,6 +4,8 @@ var (
 	FailedToConnect          = "Failed to connect to RabbitMQ"
 	FailedToOpenChannel      = "Failed to open a channel"
 	FailedToOpenQueue        = "Failed to declare a queue"
+	FailedToDeclareExchange  = "Failed to declare an exchange"
+	FailedToBindQueue        = "Failed to bind a queue"
 	FailedToPublishMessage   = "Failed to publish a message"
 	FailedToRegisterConsumer = "Failed to register a consumer"
 	FailedPublishing         = "Failed to send to Rabbit"
@@ -0,0 +1,329 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// lockID is the key of the advisory lock serializing the migrators of a
+// database.
+const lockID = 72176345
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// fileName matches the migration files, <version>_<name>.<up|down>.sql.
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Dialect is a database the migrations are written for. Every dialect has a
+// directory of its own holding the same versions, so that the schema of each
+// backend moves in step.
+type Dialect struct {
+	dir string
+	// schema creates the table recording the applied migrations.
+	schema string
+	// lock and unlock serialize the migrators of a database, they are empty
+	// when the database has no such lock.
+	lock, unlock string
+}
+
+var (
+	// Postgres holds an advisory lock while migrating.
+	Postgres = Dialect{
+		dir: "postgres",
+		schema: `CREATE TABLE IF NOT EXISTS schema_migrations ` +
+			`(version bigint PRIMARY KEY, name varchar(200) NOT NULL, applied_at timestamptz DEFAULT now() NOT NULL)`,
+		lock:   `SELECT pg_advisory_lock($1)`,
+		unlock: `SELECT pg_advisory_unlock($1)`,
+	}
+	// SQLite takes no lock, its single writer makes a concurrent migrator fail
+	// recording the version and roll back instead.
+	SQLite = Dialect{
+		dir: "sqlite",
+		schema: `CREATE TABLE IF NOT EXISTS schema_migrations ` +
+			`(version bigint PRIMARY KEY, name varchar(200) NOT NULL, applied_at datetime DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) NOT NULL)`,
+	}
+)
+
+// Migration is a versioned change of the schema and the statements reverting
+// it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration and when it was applied, nil while it is pending.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load reads the migrations of fsys ordered by version. Every migration needs
+// an up file, the down file is optional.
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, name := range names {
+		match := fileName.FindStringSubmatch(path.Base(name))
+		if match == nil {
+			continue
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migrations: version %d is used by %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrations: %d_%s has no up file", m.Version, m.Name)
+		}
+		result = append(result, *m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Migrator applies and reverts migrations. Every run holds an advisory lock so
+// that concurrent runners, like several instances starting at once, apply each
+// migration once.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	migrations []Migration
+	seed       string
+}
+
+// NewMigrator returns a migrator of db running the embedded migrations of
+// dialect.
+func NewMigrator(db *sql.DB, dialect Dialect) (*Migrator, error) {
+	dir, err := fs.Sub(files, dialect.dir)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := fs.ReadFile(dir, "seed.sql")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations, seed: string(seed)}, nil
+}
+
+// Up applies the pending migrations in order and returns them.
+func (m *Migrator) Up(ctx context.Context) (applied []Migration, err error) {
+	err = m.locked(ctx, func(conn *sql.Conn, done map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			err := run(ctx, conn, migration.Up, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("migrations: applying %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			logrus.Infof("applied migration %d_%s", migration.Version, migration.Name)
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Baseline records the migrations up to version as applied without running
+// them, for a database whose schema was created before the migrations, and
+// returns them. The schema of the database must match the one they create.
+func (m *Migrator) Baseline(ctx context.Context, version int) (recorded []Migration, err error) {
+	known := false
+	for _, migration := range m.migrations {
+		known = known || migration.Version == version
+	}
+	if !known {
+		return nil, fmt.Errorf("migrations: unknown version %d", version)
+	}
+	err = m.locked(ctx, func(conn *sql.Conn, done map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok || migration.Version > version {
+				continue
+			}
+			err := run(ctx, conn, "", `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("migrations: recording %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			logrus.Infof("recorded migration %d_%s as applied", migration.Version, migration.Name)
+			recorded = append(recorded, migration)
+		}
+		return nil
+	})
+	return recorded, err
+}
+
+// Down reverts the last steps applied migrations, latest first, and returns
+// them.
+func (m *Migrator) Down(ctx context.Context, steps int) (reverted []Migration, err error) {
+	err = m.locked(ctx, func(conn *sql.Conn, done map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migrations: %d_%s can not be reverted", migration.Version, migration.Name)
+			}
+			err := run(ctx, conn, migration.Down, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			if err != nil {
+				return fmt.Errorf("migrations: reverting %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			logrus.Infof("reverted migration %d_%s", migration.Version, migration.Name)
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status returns every migration and when it was applied.
+func (m *Migrator) Status(ctx context.Context) (result []Status, err error) {
+	err = m.locked(ctx, func(conn *sql.Conn, done map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			status := Status{Migration: migration}
+			if at, ok := done[migration.Version]; ok {
+				status.AppliedAt = &at
+			}
+			result = append(result, status)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Seed loads the sample data into a migrated database.
+func (m *Migrator) Seed(ctx context.Context) error {
+	return m.locked(ctx, func(conn *sql.Conn, done map[int]time.Time) error {
+		return run(ctx, conn, m.seed, "")
+	})
+}
+
+// locked runs fn on a connection holding the advisory lock, with the versions
+// already applied.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, done map[int]time.Time) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if errClose := conn.Close(); errClose != nil {
+			logrus.Error(errClose)
+		}
+	}()
+
+	if m.dialect.lock != "" {
+		if _, err = conn.ExecContext(ctx, m.dialect.lock, lockID); err != nil {
+			return err
+		}
+		defer func() {
+			if _, errUnlock := conn.ExecContext(context.Background(), m.dialect.unlock, lockID); errUnlock != nil {
+				logrus.Error(errUnlock)
+			}
+		}()
+	}
+
+	if _, err = conn.ExecContext(ctx, m.dialect.schema); err != nil {
+		return err
+	}
+	done, err := applied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return fn(conn, done)
+}
+
+func applied(ctx context.Context, conn *sql.Conn) (map[int]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err = rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		result[version] = at
+	}
+	return result, rows.Err()
+}
+
+// run executes the statements of script, when there are some, and the
+// bookkeeping statement, when there is one, in a single transaction.
+func run(ctx context.Context, conn *sql.Conn, script string, record string, args ...interface{}) (err error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if errRollback := tx.Rollback(); errRollback != nil {
+			logrus.Error(errRollback)
+		}
+	}()
+
+	if script != "" {
+		if _, err = tx.ExecContext(ctx, script); err != nil {
+			return err
+		}
+	}
+	if record != "" {
+		if _, err = tx.ExecContext(ctx, record, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,196 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	_ "modernc.org/sqlite"
+)
+
+func TestLoad(t *testing.T) {
+	list, err := Load(fstest.MapFS{
+		"0002_loans.up.sql":     {Data: []byte("CREATE TABLE loan ()")},
+		"0001_books.up.sql":     {Data: []byte("CREATE TABLE book ()")},
+		"0001_books.down.sql":   {Data: []byte("DROP TABLE book")},
+		"seed.sql":              {Data: []byte("INSERT INTO book DEFAULT VALUES")},
+		"0003_members.down.sql": {Data: []byte("DROP TABLE member")},
+	})
+	assert.EqualError(t, err, "migrations: 3_members has no up file")
+	assert.Nil(t, list)
+
+	list, err = Load(fstest.MapFS{
+		"0002_loans.up.sql":   {Data: []byte("CREATE TABLE loan ()")},
+		"0001_books.up.sql":   {Data: []byte("CREATE TABLE book ()")},
+		"0001_books.down.sql": {Data: []byte("DROP TABLE book")},
+		"seed.sql":            {Data: []byte("INSERT INTO book DEFAULT VALUES")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "books", Up: "CREATE TABLE book ()", Down: "DROP TABLE book"},
+		{Version: 2, Name: "loans", Up: "CREATE TABLE loan ()"},
+	}, list)
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	m, err := NewMigrator(nil, Postgres)
+	require.NoError(t, err)
+	require.NotEmpty(t, m.migrations)
+	assert.Equal(t, 1, m.migrations[0].Version)
+	assert.NotEmpty(t, m.migrations[0].Down)
+	assert.NotEmpty(t, m.seed)
+}
+
+func TestDialectsShareVersions(t *testing.T) {
+	versions := func(dialect Dialect) []string {
+		m, err := NewMigrator(nil, dialect)
+		require.NoError(t, err)
+		var result []string
+		for _, migration := range m.migrations {
+			result = append(result, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		}
+		return result
+	}
+	assert.Equal(t, versions(Postgres), versions(SQLite))
+}
+
+func TestSQLiteUpSeedDown(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:migrations?mode=memory")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	m, err := NewMigrator(db, SQLite)
+	require.NoError(t, err)
+	ctx := context.TODO()
+
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(m.migrations))
+	require.NoError(t, m.Seed(ctx))
+
+	var title string
+	var createdAt time.Time
+	require.NoError(t, db.QueryRow(`SELECT title, created_at FROM book WHERE id = 1`).Scan(&title, &createdAt))
+	assert.Equal(t, "Makan Ayam", title)
+	assert.Equal(t, time.Date(2017, 5, 18, 13, 50, 19, 0, time.UTC), createdAt.UTC())
+
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	for _, s := range status {
+		assert.NotNil(t, s.AppliedAt, s.Name)
+	}
+
+	reverted, err := m.Down(ctx, len(m.migrations))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(m.migrations))
+	var tables int
+	require.NoError(t, db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE name IN ('book', 'author')`).Scan(&tables))
+	assert.Zero(t, tables)
+}
+
+func expectLock(mock sqlmock.Sqlmock, versions ...int) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock($1)`)).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, time.Now())
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).WillReturnRows(rows)
+}
+
+func TestUpAppliesPendingMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	expectLock(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE loan ()`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations`)).WithArgs(2, "loans").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	m := &Migrator{db: db, dialect: Postgres, migrations: []Migration{
+		{Version: 1, Name: "books", Up: "CREATE TABLE book ()"},
+		{Version: 2, Name: "loans", Up: "CREATE TABLE loan ()"},
+	}}
+	applied, err := m.Up(context.TODO())
+
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, 2, applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpRollsBackFailedMigration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	expectLock(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE book ()`)).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	m := &Migrator{db: db, dialect: Postgres, migrations: []Migration{{Version: 1, Name: "books", Up: "CREATE TABLE book ()"}}}
+	applied, err := m.Up(context.TODO())
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDownRevertsLatestMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	expectLock(mock, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DROP TABLE loan`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM schema_migrations WHERE version = $1`)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	m := &Migrator{db: db, dialect: Postgres, migrations: []Migration{
+		{Version: 1, Name: "books", Up: "CREATE TABLE book ()", Down: "DROP TABLE book"},
+		{Version: 2, Name: "loans", Up: "CREATE TABLE loan ()", Down: "DROP TABLE loan"},
+	}}
+	reverted, err := m.Down(context.TODO(), 1)
+
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, 2, reverted[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBaselineRecordsWithoutRunning(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	expectLock(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations`)).WithArgs(1, "books").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	m := &Migrator{db: db, dialect: Postgres, migrations: []Migration{
+		{Version: 1, Name: "books", Up: "CREATE TABLE book ()"},
+		{Version: 2, Name: "loans", Up: "CREATE TABLE loan ()"},
+	}}
+	recorded, err := m.Baseline(context.TODO(), 1)
+
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, 1, recorded[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = m.Baseline(context.TODO(), 3)
+	assert.Error(t, err)
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS payment, fine, transfer, hold, loan, copy, member, branch, api_key, author, book CASCADE;
+
+DROP OWNED BY library;
+DROP ROLE IF EXISTS library;
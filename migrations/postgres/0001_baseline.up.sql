@@ -0,0 +1,240 @@
+-- Every table holds the rows of several tenants. The rows inserted take the
+-- tenant of the session and the row level security policies at the end limit
+-- every statement to it.
+
+CREATE TABLE book
+(
+    id         serial,
+    title      varchar,
+    content    varchar        NOT NULL,
+    author_id  int  default 0 NOT NULL,
+    updated_at date DEFAULT NULL,
+    created_at date DEFAULT NULL,
+    version    int  DEFAULT 1 NOT NULL,
+    tenant_id  varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT book_tenant_id_id_key UNIQUE (tenant_id, id)
+);
+
+CREATE TABLE author
+(
+    id         serial,
+    name       varchar(200),
+    created_at date DEFAULT NULL,
+    updated_at date DEFAULT NULL,
+    tenant_id  varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY
+        (id)
+);
+
+CREATE TABLE api_key
+(
+    id           serial,
+    name         varchar(200)          NOT NULL,
+    owner        varchar(200)          NOT NULL,
+    prefix       varchar(16)           NOT NULL,
+    key_hash     char(64)              NOT NULL UNIQUE,
+    scopes       varchar[] DEFAULT '{}' NOT NULL,
+    expires_at   timestamptz DEFAULT NULL,
+    last_used_at timestamptz DEFAULT NULL,
+    revoked_at   timestamptz DEFAULT NULL,
+    created_at   timestamptz DEFAULT now() NOT NULL,
+    tenant_id    varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE branch
+(
+    id         serial,
+    code       varchar(16)               NOT NULL,
+    name       varchar(200)              NOT NULL,
+    address    varchar(500) DEFAULT ''   NOT NULL,
+    created_at timestamptz DEFAULT now() NOT NULL,
+    updated_at timestamptz DEFAULT now() NOT NULL,
+    tenant_id  varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT branch_tenant_id_id_key UNIQUE (tenant_id, id),
+    CONSTRAINT branch_code_key UNIQUE (tenant_id, code)
+);
+
+CREATE TABLE member
+(
+    id          serial,
+    name        varchar(200)                     NOT NULL,
+    email       varchar(254)                     NOT NULL,
+    card_number varchar(32)                      NOT NULL,
+    branch_id   int                              NOT NULL,
+    status      varchar(16) DEFAULT 'active'     NOT NULL,
+    expires_at  timestamptz DEFAULT NULL,
+    created_at  timestamptz DEFAULT now()        NOT NULL,
+    updated_at  timestamptz DEFAULT now()        NOT NULL,
+    tenant_id   varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT member_tenant_id_id_key UNIQUE (tenant_id, id),
+    CONSTRAINT member_branch_id_fkey FOREIGN KEY (tenant_id, branch_id) REFERENCES branch (tenant_id, id),
+    CONSTRAINT member_email_key UNIQUE (tenant_id, email),
+    CONSTRAINT member_card_number_key UNIQUE (tenant_id, card_number),
+    CONSTRAINT member_status_check CHECK (status IN ('active', 'suspended', 'expired'))
+);
+
+CREATE TABLE copy
+(
+    id             serial,
+    book_id        int                           NOT NULL,
+    barcode        varchar(64)                   NOT NULL,
+    condition      varchar(16) DEFAULT ''        NOT NULL,
+    shelf_location varchar(64) DEFAULT ''        NOT NULL,
+    branch_id      int                           NOT NULL,
+    status         varchar(16) DEFAULT 'available' NOT NULL,
+    created_at     timestamptz DEFAULT now()     NOT NULL,
+    updated_at     timestamptz DEFAULT now()     NOT NULL,
+    tenant_id      varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT copy_tenant_id_id_key UNIQUE (tenant_id, id),
+    CONSTRAINT copy_book_id_fkey FOREIGN KEY (tenant_id, book_id) REFERENCES book (tenant_id, id) ON DELETE CASCADE,
+    CONSTRAINT copy_branch_id_fkey FOREIGN KEY (tenant_id, branch_id) REFERENCES branch (tenant_id, id),
+    CONSTRAINT copy_barcode_key UNIQUE (tenant_id, barcode),
+    CONSTRAINT copy_status_check CHECK (status IN ('available', 'on_loan', 'on_hold', 'lost', 'repair', 'in_transit'))
+);
+
+CREATE INDEX copy_book_id_idx ON copy (book_id, branch_id);
+
+CREATE TABLE loan
+(
+    id          serial,
+    copy_id     int                    NOT NULL,
+    book_id     int                    NOT NULL,
+    member_id   int                    NOT NULL,
+    loaned_at   timestamptz            NOT NULL,
+    due_at      timestamptz            NOT NULL,
+    returned_at timestamptz DEFAULT NULL,
+    renewals    int         DEFAULT 0  NOT NULL,
+    reminded_at timestamptz DEFAULT NULL,
+    noticed_at  timestamptz DEFAULT NULL,
+    tenant_id   varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT loan_tenant_id_id_key UNIQUE (tenant_id, id),
+    CONSTRAINT loan_copy_id_fkey FOREIGN KEY (tenant_id, copy_id) REFERENCES copy (tenant_id, id),
+    CONSTRAINT loan_book_id_fkey FOREIGN KEY (tenant_id, book_id) REFERENCES book (tenant_id, id),
+    CONSTRAINT loan_member_id_fkey FOREIGN KEY (tenant_id, member_id) REFERENCES member (tenant_id, id)
+);
+
+CREATE UNIQUE INDEX loan_active_copy_key ON loan (copy_id) WHERE returned_at IS NULL;
+CREATE INDEX loan_member_id_idx ON loan (member_id, loaned_at);
+CREATE INDEX loan_book_id_idx ON loan (book_id, loaned_at);
+CREATE INDEX loan_open_due_at_idx ON loan (due_at) WHERE returned_at IS NULL;
+
+CREATE TABLE hold
+(
+    id         serial,
+    book_id    int                        NOT NULL,
+    member_id  int                        NOT NULL,
+    pickup_branch_id int                  NOT NULL,
+    copy_id    int         DEFAULT NULL,
+    status     varchar(16) DEFAULT 'waiting' NOT NULL,
+    placed_at  timestamptz                NOT NULL,
+    ready_at   timestamptz DEFAULT NULL,
+    expires_at timestamptz DEFAULT NULL,
+    tenant_id  varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT hold_tenant_id_id_key UNIQUE (tenant_id, id),
+    CONSTRAINT hold_book_id_fkey FOREIGN KEY (tenant_id, book_id) REFERENCES book (tenant_id, id) ON DELETE CASCADE,
+    CONSTRAINT hold_member_id_fkey FOREIGN KEY (tenant_id, member_id) REFERENCES member (tenant_id, id) ON DELETE CASCADE,
+    CONSTRAINT hold_pickup_branch_id_fkey FOREIGN KEY (tenant_id, pickup_branch_id) REFERENCES branch (tenant_id, id),
+    CONSTRAINT hold_copy_id_fkey FOREIGN KEY (tenant_id, copy_id) REFERENCES copy (tenant_id, id) ON DELETE SET NULL (copy_id),
+    CONSTRAINT hold_status_check CHECK (status IN ('waiting', 'in_transit', 'ready', 'fulfilled', 'cancelled', 'expired'))
+);
+
+CREATE UNIQUE INDEX hold_active_member_book_key ON hold (book_id, member_id) WHERE status IN ('waiting', 'in_transit', 'ready');
+CREATE INDEX hold_queue_idx ON hold (book_id, placed_at, id) WHERE status = 'waiting';
+CREATE INDEX hold_ready_expires_at_idx ON hold (expires_at) WHERE status = 'ready';
+
+CREATE TABLE transfer
+(
+    id             serial,
+    copy_id        int                           NOT NULL,
+    from_branch_id int                           NOT NULL,
+    to_branch_id   int                           NOT NULL,
+    hold_id        int         DEFAULT NULL,
+    status         varchar(16) DEFAULT 'requested' NOT NULL,
+    requested_at   timestamptz                   NOT NULL,
+    shipped_at     timestamptz DEFAULT NULL,
+    received_at    timestamptz DEFAULT NULL,
+    tenant_id      varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT transfer_copy_id_fkey FOREIGN KEY (tenant_id, copy_id) REFERENCES copy (tenant_id, id),
+    CONSTRAINT transfer_from_branch_id_fkey FOREIGN KEY (tenant_id, from_branch_id) REFERENCES branch (tenant_id, id),
+    CONSTRAINT transfer_to_branch_id_fkey FOREIGN KEY (tenant_id, to_branch_id) REFERENCES branch (tenant_id, id),
+    CONSTRAINT transfer_hold_id_fkey FOREIGN KEY (tenant_id, hold_id) REFERENCES hold (tenant_id, id) ON DELETE SET NULL (hold_id),
+    CONSTRAINT transfer_status_check CHECK (status IN ('requested', 'in_transit', 'received', 'cancelled'))
+);
+
+CREATE UNIQUE INDEX transfer_open_copy_key ON transfer (copy_id) WHERE status IN ('requested', 'in_transit');
+CREATE INDEX transfer_from_branch_id_idx ON transfer (from_branch_id) WHERE status IN ('requested', 'in_transit');
+CREATE INDEX transfer_to_branch_id_idx ON transfer (to_branch_id) WHERE status IN ('requested', 'in_transit');
+
+CREATE TABLE fine
+(
+    id          serial,
+    member_id   int         NOT NULL,
+    loan_id     int         NOT NULL,
+    amount      bigint      NOT NULL,
+    assessed_at timestamptz NOT NULL,
+    tenant_id   varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fine_member_id_fkey FOREIGN KEY (tenant_id, member_id) REFERENCES member (tenant_id, id) ON DELETE CASCADE,
+    CONSTRAINT fine_loan_id_fkey FOREIGN KEY (tenant_id, loan_id) REFERENCES loan (tenant_id, id) ON DELETE CASCADE,
+    CONSTRAINT fine_loan_id_key UNIQUE (loan_id)
+);
+
+CREATE INDEX fine_member_id_idx ON fine (member_id);
+
+CREATE TABLE payment
+(
+    id        serial,
+    member_id int                      NOT NULL,
+    amount    bigint                   NOT NULL,
+    note      varchar(200) DEFAULT '' NOT NULL,
+    paid_at   timestamptz              NOT NULL,
+    tenant_id varchar(64) DEFAULT current_setting('app.tenant_id') NOT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT payment_member_id_fkey FOREIGN KEY (tenant_id, member_id) REFERENCES member (tenant_id, id) ON DELETE CASCADE,
+    CONSTRAINT payment_amount_check CHECK (amount > 0)
+);
+
+CREATE INDEX payment_member_id_idx ON payment (member_id);
+
+-- Rows are visible to and may be written by the sessions of their tenant only;
+-- a session without a tenant sees nothing. The policies are forced so that they
+-- apply to the table owner too, superusers still bypass them.
+DO
+$$
+    DECLARE
+        t text;
+    BEGIN
+        FOREACH t IN ARRAY ARRAY ['book', 'author', 'api_key', 'branch', 'member', 'copy', 'loan', 'hold', 'transfer', 'fine', 'payment']
+            LOOP
+                EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+                EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+                EXECUTE format('CREATE POLICY tenant_isolation ON %I USING (tenant_id = NULLIF(current_setting(''app.tenant_id'', true), ''''))', t);
+                EXECUTE format('CREATE INDEX %I ON %I (tenant_id)', t || '_tenant_id_idx', t);
+            END LOOP;
+    END
+$$;
+
+-- The service connects as library, which is subject to the policies. Its
+-- password is left to the operator.
+DO
+$$
+    BEGIN
+        IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'library') THEN
+            CREATE ROLE library LOGIN;
+        END IF;
+    END
+$$;
+
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO library;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO library;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO library;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO library;
+REVOKE ALL ON schema_migrations FROM library;
@@ -0,0 +1,15 @@
+ALTER TABLE book
+    ALTER COLUMN created_at DROP NOT NULL,
+    ALTER COLUMN created_at SET DEFAULT NULL,
+    ALTER COLUMN created_at TYPE date,
+    ALTER COLUMN updated_at DROP NOT NULL,
+    ALTER COLUMN updated_at SET DEFAULT NULL,
+    ALTER COLUMN updated_at TYPE date;
+
+ALTER TABLE author
+    ALTER COLUMN created_at DROP NOT NULL,
+    ALTER COLUMN created_at SET DEFAULT NULL,
+    ALTER COLUMN created_at TYPE date,
+    ALTER COLUMN updated_at DROP NOT NULL,
+    ALTER COLUMN updated_at SET DEFAULT NULL,
+    ALTER COLUMN updated_at TYPE date;
@@ -0,0 +1,29 @@
+-- Books and authors record when they were created and last updated, set by the
+-- database rather than by clients.
+UPDATE book
+SET created_at = COALESCE(created_at, now()),
+    updated_at = COALESCE(updated_at, created_at, now())
+WHERE created_at IS NULL
+   OR updated_at IS NULL;
+
+ALTER TABLE book
+    ALTER COLUMN created_at TYPE timestamptz,
+    ALTER COLUMN created_at SET DEFAULT now(),
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN updated_at TYPE timestamptz,
+    ALTER COLUMN updated_at SET DEFAULT now(),
+    ALTER COLUMN updated_at SET NOT NULL;
+
+UPDATE author
+SET created_at = COALESCE(created_at, now()),
+    updated_at = COALESCE(updated_at, created_at, now())
+WHERE created_at IS NULL
+   OR updated_at IS NULL;
+
+ALTER TABLE author
+    ALTER COLUMN created_at TYPE timestamptz,
+    ALTER COLUMN created_at SET DEFAULT now(),
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN updated_at TYPE timestamptz,
+    ALTER COLUMN updated_at SET DEFAULT now(),
+    ALTER COLUMN updated_at SET NOT NULL;
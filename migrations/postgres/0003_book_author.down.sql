@@ -0,0 +1,5 @@
+ALTER TABLE book
+    DROP CONSTRAINT book_author_id_fkey;
+
+ALTER TABLE author
+    DROP CONSTRAINT author_tenant_id_id_key;
@@ -0,0 +1,8 @@
+-- Books reference their author within the tenant. The books stored before are
+-- not checked, some have the author 0 the API used to accept; once they have a
+-- known author, VALIDATE CONSTRAINT book_author_id_fkey checks them too.
+ALTER TABLE author
+    ADD CONSTRAINT author_tenant_id_id_key UNIQUE (tenant_id, id);
+
+ALTER TABLE book
+    ADD CONSTRAINT book_author_id_fkey FOREIGN KEY (tenant_id, author_id) REFERENCES author (tenant_id, id) NOT VALID;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS author;
+DROP TABLE IF EXISTS book;
@@ -0,0 +1,21 @@
+-- The catalogue of a single library. SQLite deployments hold no tenants, the
+-- database file is the library.
+
+CREATE TABLE book
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    title      varchar,
+    content    varchar          NOT NULL,
+    author_id  int    DEFAULT 0 NOT NULL,
+    updated_at date DEFAULT NULL,
+    created_at date DEFAULT NULL,
+    version    int    DEFAULT 1 NOT NULL
+);
+
+CREATE TABLE author
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    name       varchar(200),
+    created_at date DEFAULT NULL,
+    updated_at date DEFAULT NULL
+);
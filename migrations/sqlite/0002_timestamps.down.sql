@@ -0,0 +1,32 @@
+CREATE TABLE book_old
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    title      varchar,
+    content    varchar          NOT NULL,
+    author_id  int    DEFAULT 0 NOT NULL,
+    updated_at date DEFAULT NULL,
+    created_at date DEFAULT NULL,
+    version    int    DEFAULT 1 NOT NULL
+);
+
+INSERT INTO book_old (id, title, content, author_id, updated_at, created_at, version)
+SELECT id, title, content, author_id, date(updated_at), date(created_at), version
+FROM book;
+
+DROP TABLE book;
+ALTER TABLE book_old RENAME TO book;
+
+CREATE TABLE author_old
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    name       varchar(200),
+    created_at date DEFAULT NULL,
+    updated_at date DEFAULT NULL
+);
+
+INSERT INTO author_old (id, name, created_at, updated_at)
+SELECT id, name, date(created_at), date(updated_at)
+FROM author;
+
+DROP TABLE author;
+ALTER TABLE author_old RENAME TO author;
@@ -0,0 +1,44 @@
+-- Books and authors record when they were created and last updated, set by the
+-- database rather than by clients. SQLite can not alter columns, so the tables
+-- are rebuilt.
+CREATE TABLE book_new
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    title      varchar,
+    content    varchar          NOT NULL,
+    author_id  int    DEFAULT 0 NOT NULL,
+    updated_at datetime DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) NOT NULL,
+    created_at datetime DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) NOT NULL,
+    version    int    DEFAULT 1 NOT NULL
+);
+
+INSERT INTO book_new (id, title, content, author_id, updated_at, created_at, version)
+SELECT id,
+       title,
+       content,
+       author_id,
+       COALESCE(updated_at, created_at, strftime('%Y-%m-%d %H:%M:%f', 'now')),
+       COALESCE(created_at, strftime('%Y-%m-%d %H:%M:%f', 'now')),
+       version
+FROM book;
+
+DROP TABLE book;
+ALTER TABLE book_new RENAME TO book;
+
+CREATE TABLE author_new
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    name       varchar(200),
+    created_at datetime DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) NOT NULL,
+    updated_at datetime DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) NOT NULL
+);
+
+INSERT INTO author_new (id, name, created_at, updated_at)
+SELECT id,
+       name,
+       COALESCE(created_at, strftime('%Y-%m-%d %H:%M:%f', 'now')),
+       COALESCE(updated_at, created_at, strftime('%Y-%m-%d %H:%M:%f', 'now'))
+FROM author;
+
+DROP TABLE author;
+ALTER TABLE author_new RENAME TO author;
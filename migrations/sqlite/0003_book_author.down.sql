@@ -0,0 +1,17 @@
+CREATE TABLE book_old
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    title      varchar,
+    content    varchar          NOT NULL,
+    author_id  int    DEFAULT 0 NOT NULL,
+    updated_at datetime DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) NOT NULL,
+    created_at datetime DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) NOT NULL,
+    version    int    DEFAULT 1 NOT NULL
+);
+
+INSERT INTO book_old (id, title, content, author_id, updated_at, created_at, version)
+SELECT id, title, content, author_id, updated_at, created_at, version
+FROM book;
+
+DROP TABLE book;
+ALTER TABLE book_old RENAME TO book;
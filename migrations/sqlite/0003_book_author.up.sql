@@ -0,0 +1,20 @@
+-- Books reference their author. SQLite can not add a constraint to a table, so
+-- the table is rebuilt, which fails on a book of an unknown author: give such
+-- books a known author first.
+CREATE TABLE book_new
+(
+    id         integer PRIMARY KEY AUTOINCREMENT,
+    title      varchar,
+    content    varchar          NOT NULL,
+    author_id  int    DEFAULT 0 NOT NULL REFERENCES author (id),
+    updated_at datetime DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) NOT NULL,
+    created_at datetime DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')) NOT NULL,
+    version    int    DEFAULT 1 NOT NULL
+);
+
+INSERT INTO book_new (id, title, content, author_id, updated_at, created_at, version)
+SELECT id, title, content, author_id, updated_at, created_at, version
+FROM book;
+
+DROP TABLE book;
+ALTER TABLE book_new RENAME TO book;
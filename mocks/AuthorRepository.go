@@ -14,6 +14,29 @@ type AuthorRepository struct {
 	mock.Mock
 }
 
+// FetchByIDs provides a mock function with given fields: ctx, ids
+func (_m *AuthorRepository) FetchByIDs(ctx context.Context, ids []int) (map[int]domain.Author, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 map[int]domain.Author
+	if rf, ok := ret.Get(0).(func(context.Context, []int) map[int]domain.Author); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]domain.Author)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetById provides a mock function with given fields: ctx, id
 func (_m *AuthorRepository) GetById(ctx context.Context, id int) (domain.Author, error) {
 	ret := _m.Called(ctx, id)
@@ -28,13 +28,13 @@ func (_m *BookRepository) Add(ctx context.Context, book *domain.Book) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *BookRepository) Delete(ctx context.Context, id int) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *BookRepository) Delete(ctx context.Context, id int, version int) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -28,13 +28,13 @@ func (_m *BookUseCase) Add(ctx context.Context, book *domain.Book) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *BookUseCase) Delete(ctx context.Context, id int) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *BookUseCase) Delete(ctx context.Context, id int, version int) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -86,6 +86,27 @@ func (_m *BookUseCase) GetById(ctx context.Context, id int) (domain.Book, error)
 	return r0, r1
 }
 
+// Patch provides a mock function with given fields: ctx, id, version, patchType, patch
+func (_m *BookUseCase) Patch(ctx context.Context, id int, version int, patchType domain.PatchType, patch []byte) (domain.Book, error) {
+	ret := _m.Called(ctx, id, version, patchType, patch)
+
+	var r0 domain.Book
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, domain.PatchType, []byte) domain.Book); ok {
+		r0 = rf(ctx, id, version, patchType, patch)
+	} else {
+		r0 = ret.Get(0).(domain.Book)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, domain.PatchType, []byte) error); ok {
+		r1 = rf(ctx, id, version, patchType, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, book
 func (_m *BookUseCase) Update(ctx context.Context, book *domain.Book) error {
 	ret := _m.Called(ctx, book)
@@ -0,0 +1,153 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/i18n"
+)
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes a single failed validation rule of a request field
+type FieldError struct {
+	Field  string `json:"field"`
+	Rule   string `json:"rule"`
+	Detail string `json:"detail"`
+}
+
+type kind struct {
+	err    error
+	status int
+	slug   string
+}
+
+// kinds maps the domain errors onto HTTP statuses, the first match wins.
+var kinds = []kind{
+	{err: domain.ErrNotFound, status: http.StatusNotFound, slug: "not-found"},
+	{err: domain.ErrConflict, status: http.StatusConflict, slug: "conflict"},
+	{err: domain.ErrVersionConflict, status: http.StatusPreconditionFailed, slug: "version-conflict"},
+	{err: domain.ErrInvalidPatch, status: http.StatusUnprocessableEntity, slug: "invalid-patch"},
+	{err: domain.ErrUnauthorized, status: http.StatusUnauthorized, slug: "unauthorized"},
+	{err: domain.ErrForbidden, status: http.StatusForbidden, slug: "forbidden"},
+	{err: domain.ErrNotAvailable, status: http.StatusConflict, slug: "not-available"},
+	{err: domain.ErrRenewalLimit, status: http.StatusConflict, slug: "renewal-limit"},
+	{err: domain.ErrMembershipInactive, status: http.StatusUnprocessableEntity, slug: "membership-inactive"},
+	{err: domain.ErrFinesOutstanding, status: http.StatusUnprocessableEntity, slug: "fines-outstanding"},
+	{err: domain.ErrUnknownTenant, status: http.StatusBadRequest, slug: "unknown-tenant"},
+	{err: domain.ErrInternalServerError, status: http.StatusInternalServerError, slug: "internal-error"},
+}
+
+// Middleware renders every error returned by the next handler as an
+// application/problem+json response.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+			if err == nil {
+				return nil
+			}
+			if c.Response().Committed {
+				logrus.Error(err)
+				return nil
+			}
+			return Write(c, New(err, c.Request().URL.Path, i18n.Locale(c.Request().Context())))
+		}
+	}
+}
+
+// New builds the problem describing err for the request to instance, with the
+// messages translated to locale.
+func New(err error, instance string, locale string) Problem {
+	p := Problem{Status: StatusCode(err), Instance: instance, Detail: i18n.Error(locale, err)}
+	p.Title = http.StatusText(p.Status)
+	p.Type = "about:blank"
+
+	var validationErrors validator.ValidationErrors
+	var invalidID *domain.InvalidIDError
+	var httpError *echo.HTTPError
+	switch {
+	case errors.As(err, &invalidID):
+		p.Detail = i18n.T(locale, i18n.ValidationFailed)
+		p.Type = typeURI("validation")
+		p.Errors = []FieldError{{Field: invalidID.Param, Rule: "numeric", Detail: i18n.T(locale, i18n.InvalidID, invalidID.Param)}}
+	case errors.As(err, &validationErrors):
+		p.Detail = i18n.T(locale, i18n.ValidationFailed)
+		p.Type = typeURI("validation")
+		for _, fe := range validationErrors {
+			p.Errors = append(p.Errors, FieldError{
+				Field:  fe.Field(),
+				Rule:   fe.Tag(),
+				Detail: fe.Translate(i18n.Translator(locale)),
+			})
+		}
+	case errors.As(err, &httpError):
+		p.Detail = fmt.Sprint(httpError.Message)
+	default:
+		for _, k := range kinds {
+			if errors.Is(err, k.err) {
+				p.Type = typeURI(k.slug)
+				break
+			}
+		}
+	}
+
+	if p.Status >= http.StatusInternalServerError {
+		logrus.Error(err)
+		p.Detail = i18n.Error(locale, domain.ErrInternalServerError)
+	}
+	return p
+}
+
+// StatusCode returns the HTTP status matching err.
+func StatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	var httpError *echo.HTTPError
+	if errors.As(err, &httpError) {
+		return httpError.Code
+	}
+	var validationErrors validator.ValidationErrors
+	var invalidID *domain.InvalidIDError
+	if errors.As(err, &validationErrors) || errors.As(err, &invalidID) {
+		return http.StatusBadRequest
+	}
+	for _, k := range kinds {
+		if errors.Is(err, k.err) {
+			return k.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// Write sends p as the response.
+func Write(c echo.Context, p Problem) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return c.Blob(p.Status, MIMEApplicationProblemJSON, body)
+}
+
+func typeURI(slug string) string {
+	return "/problems/" + slug
+}
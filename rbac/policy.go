@@ -0,0 +1,60 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// Anonymous is the role of callers without a principal in their context
+const Anonymous = "anonymous"
+
+// Policy grants permissions to roles
+type Policy struct {
+	roles map[string]map[domain.Permission]bool
+}
+
+// NewPolicy builds a policy from the permission names granted to each role.
+func NewPolicy(roles map[string][]string) (*Policy, error) {
+	known := make(map[domain.Permission]bool)
+	for _, permission := range domain.Permissions {
+		known[permission] = true
+	}
+
+	p := &Policy{roles: make(map[string]map[domain.Permission]bool)}
+	for role, permissions := range roles {
+		p.roles[role] = make(map[domain.Permission]bool)
+		for _, name := range permissions {
+			permission := domain.Permission(name)
+			if !known[permission] {
+				return nil, fmt.Errorf("rbac: role %q has unknown permission %q", role, name)
+			}
+			p.roles[role][permission] = true
+		}
+	}
+	return p, nil
+}
+
+// Authorize allows the operation when the principal in ctx holds the
+// permission as a scope or any of its roles is granted the permission.
+func (p *Policy) Authorize(ctx context.Context, permission domain.Permission) error {
+	roles := []string{Anonymous}
+	subject := Anonymous
+	if principal, ok := domain.PrincipalFromContext(ctx); ok {
+		roles = principal.Roles
+		subject = principal.Subject
+		for _, scope := range principal.Scopes {
+			if domain.Permission(scope) == permission {
+				return nil
+			}
+		}
+	}
+
+	for _, role := range roles {
+		if p.roles[role][permission] {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s may not %s: %w", subject, permission, domain.ErrForbidden)
+}
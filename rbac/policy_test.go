@@ -0,0 +1,55 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+)
+
+func TestAuthorize(t *testing.T) {
+	policy, err := NewPolicy(map[string][]string{
+		"reader": {"books:read"},
+		"editor": {"books:read", "books:write"},
+		"admin":  {"books:read", "books:write", "books:delete"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		roles      []string
+		anonymous  bool
+		permission domain.Permission
+		allowed    bool
+	}{
+		{name: "reader reads", roles: []string{"reader"}, permission: domain.PermissionReadBooks, allowed: true},
+		{name: "reader writes", roles: []string{"reader"}, permission: domain.PermissionWriteBooks},
+		{name: "editor writes", roles: []string{"editor"}, permission: domain.PermissionWriteBooks, allowed: true},
+		{name: "editor deletes", roles: []string{"editor"}, permission: domain.PermissionDeleteBooks},
+		{name: "any role allows", roles: []string{"reader", "admin"}, permission: domain.PermissionDeleteBooks, allowed: true},
+		{name: "unknown role", roles: []string{"guest"}, permission: domain.PermissionReadBooks},
+		{name: "anonymous", anonymous: true, permission: domain.PermissionReadBooks},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			if !tt.anonymous {
+				ctx = domain.NewContextWithPrincipal(ctx, domain.Principal{Subject: "user-1", Roles: tt.roles})
+			}
+			err := policy.Authorize(ctx, tt.permission)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrForbidden)
+			}
+		})
+	}
+}
+
+func TestNewPolicyUnknownPermission(t *testing.T) {
+	_, err := NewPolicy(map[string][]string{"reader": {"books:burn"}})
+	assert.Error(t, err)
+}
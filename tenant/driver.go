@@ -0,0 +1,176 @@
+package tenant
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// setTenant stores the tenant in the session setting the row level security
+// policies of every table compare the tenant_id column with.
+const setTenant = `SELECT set_config('app.tenant_id', $1, false)`
+
+// NewConnector returns a connector whose connections are scoped to the tenant
+// carried by the context of every statement and transaction run on them. A
+// context without a tenant scopes the connection to no tenant, so the row level
+// security policies hide every row.
+func NewConnector(c driver.Connector) driver.Connector {
+	return &connector{c}
+}
+
+type connector struct {
+	driver.Connector
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	dc, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: dc}, nil
+}
+
+// conn remembers the tenant it is scoped to so that the setting is only sent
+// when the tenant changes.
+type conn struct {
+	driver.Conn
+	tenantID string
+	scoped   bool
+}
+
+func (c *conn) scope(ctx context.Context) error {
+	id, _ := domain.TenantFromContext(ctx)
+	if c.scoped && c.tenantID == id {
+		return nil
+	}
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return errors.New("tenant: driver connection can not execute statements")
+	}
+	if _, err := execer.ExecContext(ctx, setTenant, []driver.NamedValue{{Ordinal: 1, Value: id}}); err != nil {
+		return err
+	}
+	c.tenantID, c.scoped = id, true
+	return nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var ds driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		ds, err = preparer.PrepareContext(ctx, query)
+	} else {
+		ds, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{Stmt: ds, conn: c}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	// The tenant is set before the transaction begins so that rolling it back
+	// keeps the connection scoped, unless a statement of the transaction scopes
+	// it to another tenant.
+	if err := c.scope(ctx); err != nil {
+		return nil, err
+	}
+	var dt driver.Tx
+	var err error
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		dt, err = beginner.BeginTx(ctx, opts)
+	} else if opts.Isolation != driver.IsolationLevel(0) || opts.ReadOnly {
+		return nil, errors.New("tenant: driver connection does not support transaction options")
+	} else {
+		dt, err = c.Conn.Begin()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tx{Tx: dt, conn: c, tenantID: c.tenantID}, nil
+}
+
+// tx restores the scope of its connection when rolled back: Postgres reverts
+// the settings changed by the transaction, the tenant set by one of its
+// statements included.
+type tx struct {
+	driver.Tx
+	conn     *conn
+	tenantID string
+}
+
+func (t *tx) Rollback() error {
+	if t.conn.tenantID != t.tenantID {
+		t.conn.scoped = false
+	}
+	return t.Tx.Rollback()
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// stmt scopes the connection it was prepared on before every execution.
+type stmt struct {
+	driver.Stmt
+	conn *conn
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := s.conn.scope(ctx); err != nil {
+		return nil, err
+	}
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+	return s.Stmt.Exec(values(args))
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := s.conn.scope(ctx); err != nil {
+		return nil, err
+	}
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		return queryer.QueryContext(ctx, args)
+	}
+	return s.Stmt.Query(values(args))
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	list := make([]driver.Value, len(args))
+	for i, nv := range args {
+		list[i] = nv.Value
+	}
+	return list
+}
@@ -0,0 +1,49 @@
+package tenant
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// Middleware stores the tenant resolved by r in the request context. Requests
+// naming no tenant pass through so that their access token may bind one.
+func Middleware(r *Resolver) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id, ok, err := r.Resolve(c.Request())
+			if err != nil {
+				return err
+			}
+			if ok {
+				ctx := domain.NewContextWithTenant(c.Request().Context(), id)
+				c.SetRequest(c.Request().WithContext(ctx))
+			}
+			return next(c)
+		}
+	}
+}
+
+// Require rejects the requests left without a tenant by the middleware before
+// it with domain.ErrUnknownTenant.
+func Require() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := domain.TenantFromContext(c.Request().Context()); !ok {
+				return fmt.Errorf("request names no tenant: %w", domain.ErrUnknownTenant)
+			}
+			return next(c)
+		}
+	}
+}
+
+// Enforce returns Require when r has tenants and nothing otherwise, so that a
+// single library, configured without tenants, serves requests naming none.
+func Enforce(r *Resolver) []echo.MiddlewareFunc {
+	if len(r.IDs()) == 0 {
+		return nil
+	}
+	return []echo.MiddlewareFunc{Require()}
+}
@@ -0,0 +1,74 @@
+package tenant
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// HeaderTenant names the tenant of a request explicitly.
+const HeaderTenant = "X-Tenant-ID"
+
+// Resolver finds the tenant of a request from its X-Tenant-ID header or the
+// host it is sent to.
+type Resolver struct {
+	tenants map[string]bool
+	hosts   map[string]string
+}
+
+// NewResolver returns a resolver for the tenants, each mapped to the hosts it is
+// served on. A host may serve a single tenant.
+func NewResolver(hosts map[string][]string) (*Resolver, error) {
+	r := &Resolver{tenants: make(map[string]bool), hosts: make(map[string]string)}
+	for id, names := range hosts {
+		r.tenants[id] = true
+		for _, name := range names {
+			name = strings.ToLower(name)
+			if other, ok := r.hosts[name]; ok {
+				return nil, fmt.Errorf("tenant: host %q serves both %q and %q", name, other, id)
+			}
+			r.hosts[name] = id
+		}
+	}
+	return r, nil
+}
+
+// IDs returns the known tenants in order.
+func (r *Resolver) IDs() []string {
+	ids := make([]string, 0, len(r.tenants))
+	for id := range r.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Resolve returns the tenant named by the X-Tenant-ID header of req, else the
+// tenant its host is served for or named by the first label of its host. It
+// reports false when req names no tenant and fails with domain.ErrUnknownTenant
+// when the header names an unknown one.
+func (r *Resolver) Resolve(req *http.Request) (string, bool, error) {
+	if id := req.Header.Get(HeaderTenant); id != "" {
+		if !r.tenants[id] {
+			return "", false, fmt.Errorf("tenant %q: %w", id, domain.ErrUnknownTenant)
+		}
+		return id, true, nil
+	}
+
+	host, _, err := net.SplitHostPort(req.Host)
+	if err != nil {
+		host = req.Host
+	}
+	host = strings.ToLower(host)
+	if id, ok := r.hosts[host]; ok {
+		return id, true, nil
+	}
+	if i := strings.IndexByte(host, '.'); i > 0 && r.tenants[host[:i]] {
+		return host[:i], true, nil
+	}
+	return "", false, nil
+}
@@ -0,0 +1,178 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+)
+
+func TestResolve(t *testing.T) {
+	r, err := NewResolver(map[string][]string{"central": {"library.example.com", "localhost"}, "east": nil})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"central", "east"}, r.IDs())
+
+	tests := []struct {
+		name     string
+		host     string
+		header   string
+		expected string
+		ok       bool
+		err      error
+	}{
+		{name: "header", host: "library.example.com", header: "east", expected: "east", ok: true},
+		{name: "unknown header", host: "library.example.com", header: "west", err: domain.ErrUnknownTenant},
+		{name: "host", host: "Library.Example.com", expected: "central", ok: true},
+		{name: "host with port", host: "localhost:9000", expected: "central", ok: true},
+		{name: "subdomain", host: "east.example.com", expected: "east", ok: true},
+		{name: "unknown host", host: "west.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+			req.Host = tt.host
+			if tt.header != "" {
+				req.Header.Set(HeaderTenant, tt.header)
+			}
+			id, ok, err := r.Resolve(req)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, id)
+		})
+	}
+
+	_, err = NewResolver(map[string][]string{"central": {"localhost"}, "east": {"localhost"}})
+	assert.Error(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	r, err := NewResolver(map[string][]string{"central": {"localhost"}})
+	require.NoError(t, err)
+
+	var tenantID string
+	next := func(c echo.Context) error {
+		tenantID, _ = domain.TenantFromContext(c.Request().Context())
+		return c.NoContent(http.StatusNoContent)
+	}
+	handler := Middleware(r)(Require()(next))
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req.Host = "localhost"
+	require.NoError(t, handler(e.NewContext(req, httptest.NewRecorder())))
+	assert.Equal(t, "central", tenantID)
+
+	req = httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req.Host = "example.com"
+	err = handler(e.NewContext(req, httptest.NewRecorder()))
+	assert.ErrorIs(t, err, domain.ErrUnknownTenant)
+}
+
+func TestEnforceWithoutTenants(t *testing.T) {
+	r, err := NewResolver(nil)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Use(Middleware(r))
+	e.GET("/api/v1/books", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, Enforce(r)...)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(echo.GET, "/api/v1/books", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestEnforceWithTenants(t *testing.T) {
+	r, err := NewResolver(map[string][]string{"central": {"localhost"}})
+	require.NoError(t, err)
+
+	next := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	middleware := Enforce(r)
+	require.Len(t, middleware, 1)
+
+	req := httptest.NewRequest(echo.GET, "/api/v1/books", nil)
+	req.Host = "example.com"
+	err = Middleware(r)(middleware[0](next))(echo.New().NewContext(req, httptest.NewRecorder()))
+	assert.ErrorIs(t, err, domain.ErrUnknownTenant)
+}
+
+// fakeConn records the statements run on it.
+type fakeConn struct {
+	statements *[]string
+}
+
+func (c fakeConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c fakeConn) Driver() driver.Driver                        { return nil }
+func (c fakeConn) Prepare(string) (driver.Stmt, error)          { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                                 { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                    { return c, nil }
+func (c fakeConn) Commit() error                                { return nil }
+func (c fakeConn) Rollback() error                              { return nil }
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if query == setTenant {
+		query += " " + args[0].Value.(string)
+	}
+	*c.statements = append(*c.statements, query)
+	return driver.RowsAffected(1), nil
+}
+
+func TestConnectorRescopesAfterRollback(t *testing.T) {
+	var statements []string
+	db := sql.OpenDB(NewConnector(fakeConn{&statements}))
+	db.SetMaxOpenConns(1)
+	central := domain.NewContextWithTenant(context.TODO(), "central")
+	east := domain.NewContextWithTenant(context.TODO(), "east")
+
+	tx, err := db.BeginTx(central, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(east, "DELETE FROM book")
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+	// The rollback scoped the connection to central again.
+	_, err = db.ExecContext(east, "DELETE FROM author")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		setTenant + " central",
+		setTenant + " east", "DELETE FROM book",
+		setTenant + " east", "DELETE FROM author",
+	}, statements)
+}
+
+func TestConnectorScopesConnections(t *testing.T) {
+	var statements []string
+	db := sql.OpenDB(NewConnector(fakeConn{&statements}))
+	db.SetMaxOpenConns(1)
+	central := domain.NewContextWithTenant(context.TODO(), "central")
+
+	_, err := db.ExecContext(central, "DELETE FROM book")
+	require.NoError(t, err)
+	_, err = db.ExecContext(central, "DELETE FROM author")
+	require.NoError(t, err)
+	tx, err := db.BeginTx(domain.NewContextWithTenant(context.TODO(), "east"), nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+	_, err = db.ExecContext(context.TODO(), "DELETE FROM copy")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		setTenant + " central", "DELETE FROM book", "DELETE FROM author",
+		setTenant + " east",
+		setTenant + " ", "DELETE FROM copy",
+	}, statements)
+}
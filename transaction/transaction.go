@@ -0,0 +1,165 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+)
+
+const (
+	serializationFailure = "40001"
+	deadlockDetected     = "40P01"
+)
+
+// Tx is the transaction a unit of work runs its function in.
+type Tx struct {
+	*sql.Tx
+	committed []func()
+}
+
+// AfterCommit registers fn to run once the transaction is committed, such as
+// invalidating what a cache holds of the rows it changed.
+func (tx *Tx) AfterCommit(fn func()) {
+	tx.committed = append(tx.committed, fn)
+}
+
+// Binder returns the repositories running their statements in tx.
+type Binder func(tx *Tx) domain.Repositories
+
+// Options configure the transactions of a Manager.
+type Options struct {
+	// Isolation is the isolation level of the transactions, the default one of
+	// the database when zero.
+	Isolation sql.IsolationLevel
+	// Retries is how many times a transaction failing on a serialization
+	// failure or a deadlock is run again.
+	Retries int
+	// Backoff is the wait before the first retry, doubled on each of the next.
+	Backoff time.Duration
+}
+
+// Manager is the domain.UnitOfWork of a database.
+type Manager struct {
+	db      *sql.DB
+	bind    Binder
+	options Options
+}
+
+// NewManager returns the unit of work running functions in transactions of db
+// with the repositories bound to them by bind.
+func NewManager(db *sql.DB, bind Binder, options Options) *Manager {
+	return &Manager{db: db, bind: bind, options: options}
+}
+
+// Do runs fn in a transaction, retrying it as configured when the database
+// aborts it because of a concurrent one.
+func (m *Manager) Do(ctx context.Context, fn func(ctx context.Context, repos domain.Repositories) error) error {
+	backoff := m.options.Backoff
+	for attempt := 0; ; attempt++ {
+		err := m.run(ctx, fn)
+		if err == nil || attempt >= m.options.Retries || !retryable(err) {
+			return err
+		}
+		logrus.Warnf("transaction attempt %d: %v", attempt+1, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (m *Manager) run(ctx context.Context, fn func(ctx context.Context, repos domain.Repositories) error) (err error) {
+	sqlTx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: m.options.Isolation})
+	if err != nil {
+		return err
+	}
+	tx := &Tx{Tx: sqlTx}
+	defer func() {
+		if p := recover(); p != nil {
+			rollback(tx)
+			panic(p)
+		}
+		if err != nil {
+			rollback(tx)
+		}
+	}()
+
+	if err = fn(ctx, m.bind(tx)); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	for _, committed := range tx.committed {
+		committed()
+	}
+	return nil
+}
+
+func rollback(tx *Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		logrus.Error(err)
+	}
+}
+
+// retryable reports whether err aborted a transaction that may succeed when
+// run again.
+func retryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == serializationFailure || pqErr.Code == deadlockDetected
+}
+
+// ParseIsolation returns the isolation level named as in SQL, such as
+// "repeatable read". The empty name is the default level of the database.
+func ParseIsolation(name string) (sql.IsolationLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "default":
+		return sql.LevelDefault, nil
+	case "read committed":
+		return sql.LevelReadCommitted, nil
+	case "repeatable read":
+		return sql.LevelRepeatableRead, nil
+	case "serializable":
+		return sql.LevelSerializable, nil
+	}
+	return sql.LevelDefault, fmt.Errorf("unknown isolation level %q", name)
+}
+
+// None is the unit of work of repositories having no transactions, such as
+// the in-memory ones: functions run once, with repos, and are not atomic.
+func None(repos domain.Repositories) domain.UnitOfWork {
+	return none(repos)
+}
+
+type none domain.Repositories
+
+func (n none) Do(ctx context.Context, fn func(ctx context.Context, repos domain.Repositories) error) error {
+	var committed []func()
+	repos := domain.Repositories(n)
+	repos.AfterCommit = func(fn func()) {
+		committed = append(committed, fn)
+	}
+	if err := fn(ctx, repos); err != nil {
+		return err
+	}
+	for _, fn := range committed {
+		fn()
+	}
+	return nil
+}
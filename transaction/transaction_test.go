@@ -0,0 +1,135 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+)
+
+// bind returns the book repository of every transaction, registering a
+// function counting the commits.
+func bind(books domain.BookRepository, commits *int) Binder {
+	return func(tx *Tx) domain.Repositories {
+		tx.AfterCommit(func() { *commits++ })
+		return domain.Repositories{Books: books}
+	}
+}
+
+func TestDoCommits(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	books := new(mocks.BookRepository)
+	var commits int
+	err = NewManager(db, bind(books, &commits), Options{}).Do(context.TODO(), func(ctx context.Context, repos domain.Repositories) error {
+		assert.Equal(t, books, repos.Books)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, commits)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDoRollsBackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	var commits int
+	err = NewManager(db, bind(nil, &commits), Options{Retries: 3}).Do(context.TODO(), func(ctx context.Context, repos domain.Repositories) error {
+		return domain.ErrVersionConflict
+	})
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	assert.Zero(t, commits)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDoRollsBackOnPanic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	var commits int
+	manager := NewManager(db, bind(nil, &commits), Options{})
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = manager.Do(context.TODO(), func(ctx context.Context, repos domain.Repositories) error {
+			panic("boom")
+		})
+	})
+	assert.Zero(t, commits)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDoRetriesSerializationFailures(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(&pq.Error{Code: deadlockDetected})
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	var commits, runs int
+	err = NewManager(db, bind(nil, &commits), Options{Retries: 2}).Do(context.TODO(), func(ctx context.Context, repos domain.Repositories) error {
+		runs++
+		if runs == 1 {
+			return &pq.Error{Code: serializationFailure}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, 1, commits)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDoGivesUpAfterRetries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+	}
+
+	var runs int
+	err = NewManager(db, bind(nil, new(int)), Options{Retries: 1}).Do(context.TODO(), func(ctx context.Context, repos domain.Repositories) error {
+		runs++
+		return &pq.Error{Code: serializationFailure}
+	})
+	var pqErr *pq.Error
+	require.True(t, errors.As(err, &pqErr))
+	assert.Equal(t, pq.ErrorCode(serializationFailure), pqErr.Code)
+	assert.Equal(t, 2, runs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestParseIsolation(t *testing.T) {
+	tests := map[string]sql.IsolationLevel{
+		"":                sql.LevelDefault,
+		"read committed":  sql.LevelReadCommitted,
+		"Repeatable Read": sql.LevelRepeatableRead,
+		"serializable":    sql.LevelSerializable,
+	}
+	for name, want := range tests {
+		got, err := ParseIsolation(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, name)
+	}
+
+	_, err := ParseIsolation("snapshot")
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/validation"
+)
+
+const ID = "id"
+
+type TransferHandler struct {
+	TUseCase  domain.TransferUseCase
+	validator *validator.Validate
+}
+
+// NewTransferHandler registers the transfer routes on e, each of them wrapped
+// in middleware.
+func NewTransferHandler(e *echo.Echo, us domain.TransferUseCase, middleware ...echo.MiddlewareFunc) {
+	handler := &TransferHandler{TUseCase: us, validator: validation.Validator()}
+	handler.Register(e.Group("/api/v1", middleware...))
+}
+
+func (h *TransferHandler) Register(g *echo.Group) {
+	g.POST("/transfers", h.Request)
+	g.GET("/transfers/:id", h.GetById)
+	g.POST("/transfers/:id/ship", h.Ship)
+	g.POST("/transfers/:id/receive", h.Receive)
+	g.POST("/transfers/:id/cancel", h.Cancel)
+	g.GET("/branches/:id/transfers", h.FetchByBranch)
+}
+
+func (h TransferHandler) Request(c echo.Context) error {
+	var transfer domain.Transfer
+	if err := c.Bind(&transfer); err != nil {
+		return err
+	}
+	if err := h.validator.Struct(&transfer); err != nil {
+		return err
+	}
+
+	if err := h.TUseCase.Request(c.Request().Context(), &transfer); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, transfer)
+}
+
+func (h TransferHandler) GetById(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	transfer, err := h.TUseCase.GetById(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, transfer)
+}
+
+func (h TransferHandler) Ship(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	transfer, err := h.TUseCase.Ship(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, transfer)
+}
+
+func (h TransferHandler) Receive(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	transfer, err := h.TUseCase.Receive(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, transfer)
+}
+
+func (h TransferHandler) Cancel(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	transfer, err := h.TUseCase.Cancel(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, transfer)
+}
+
+// FetchByBranch returns the open transfers leaving or reaching the branch.
+func (h TransferHandler) FetchByBranch(c echo.Context) error {
+	id, err := pathID(c)
+	if err != nil {
+		return err
+	}
+
+	transfers, err := h.TUseCase.FetchByBranch(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, transfers)
+}
+
+func pathID(c echo.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param(ID))
+	if err != nil {
+		return 0, &domain.InvalidIDError{Param: ID, Value: c.Param(ID)}
+	}
+	return id, nil
+}
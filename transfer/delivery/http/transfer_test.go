@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/mocks"
+	"github.com/bxcodec/library/problem"
+	"github.com/bxcodec/library/validation"
+)
+
+func TestRequest(t *testing.T) {
+	mockUCase := new(mocks.TransferUseCase)
+	mockUCase.On("Request", mock.Anything, &domain.Transfer{CopyID: 5, ToBranchID: 2}).
+		Run(func(args mock.Arguments) { args.Get(1).(*domain.Transfer).Status = domain.TransferRequested }).
+		Return(nil).Once()
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/api/v1/transfers", strings.NewReader(`{"copy_id":5,"to_branch_id":2}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	handler := TransferHandler{TUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, handler.Request(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"requested"`)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestReceiveNotInTransit(t *testing.T) {
+	mockUCase := new(mocks.TransferUseCase)
+	mockUCase.On("Receive", mock.Anything, 8).Return(domain.Transfer{}, domain.ErrConflict).Once()
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(echo.POST, "/api/v1/transfers/8/receive", nil), rec)
+	c.SetParamNames(ID)
+	c.SetParamValues("8")
+	handler := TransferHandler{TUseCase: mockUCase, validator: validation.Validator()}
+	require.NoError(t, problem.Middleware()(handler.Receive)(c))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
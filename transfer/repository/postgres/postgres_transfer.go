@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/library/domain"
+)
+
+const (
+	selectTransfer    = `SELECT id, copy_id, from_branch_id, to_branch_id, hold_id, status, requested_at, shipped_at, received_at FROM transfer`
+	returningTransfer = ` RETURNING id, copy_id, from_branch_id, to_branch_id, hold_id, status, requested_at, shipped_at, received_at`
+
+	foreignKeyViolation = "23503"
+)
+
+type postgresTransferRepository struct {
+	Conn *sql.DB
+}
+
+func NewPostgresTransferRepository(Conn *sql.DB) domain.TransferRepository {
+	return &postgresTransferRepository{Conn}
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func fetch(ctx context.Context, q queryer, query string, args ...interface{}) ([]domain.Transfer, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result := make([]domain.Transfer, 0)
+	for rows.Next() {
+		transfer := domain.Transfer{}
+		err = rows.Scan(
+			&transfer.ID,
+			&transfer.CopyID,
+			&transfer.FromBranchID,
+			&transfer.ToBranchID,
+			&transfer.HoldID,
+			&transfer.Status,
+			&transfer.RequestedAt,
+			&transfer.ShippedAt,
+			&transfer.ReceivedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, transfer)
+	}
+	return result, rows.Err()
+}
+
+// getOne returns the single transfer of the query, or fails with err when
+// there is none.
+func getOne(ctx context.Context, q queryer, err error, query string, args ...interface{}) (domain.Transfer, error) {
+	list, errFetch := fetch(ctx, q, query, args...)
+	if errFetch != nil {
+		return domain.Transfer{}, errFetch
+	}
+	if len(list) == 0 {
+		return domain.Transfer{}, err
+	}
+	return list[0], nil
+}
+
+func (p *postgresTransferRepository) GetById(ctx context.Context, id int) (domain.Transfer, error) {
+	return getOne(ctx, p.Conn, fmt.Errorf("transfer %d: %w", id, domain.ErrNotFound), selectTransfer+` WHERE id = $1`, id)
+}
+
+// FetchByBranch returns the open transfers leaving or reaching the branch,
+// oldest first.
+func (p *postgresTransferRepository) FetchByBranch(ctx context.Context, branchID int) ([]domain.Transfer, error) {
+	return fetch(ctx, p.Conn, selectTransfer+` WHERE (from_branch_id = $1 OR to_branch_id = $1) AND status IN ($2, $3) ORDER BY requested_at, id`,
+		branchID, domain.TransferRequested, domain.TransferInTransit)
+}
+
+// Add requests the transfer of an available copy from its branch and marks the
+// copy in transit.
+func (p *postgresTransferRepository) Add(ctx context.Context, transfer *domain.Transfer) (err error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer rollback(tx, &err)
+
+	var status domain.CopyStatus
+	err = tx.QueryRowContext(ctx, `SELECT branch_id, status FROM copy WHERE id = $1 FOR UPDATE`, transfer.CopyID).
+		Scan(&transfer.FromBranchID, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("copy %d: %w", transfer.CopyID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+	if status != domain.CopyAvailable {
+		return fmt.Errorf("copy %d is %s: %w", transfer.CopyID, status, domain.ErrNotAvailable)
+	}
+	if transfer.FromBranchID == transfer.ToBranchID {
+		return fmt.Errorf("copy %d is at branch %d already: %w", transfer.CopyID, transfer.ToBranchID, domain.ErrConflict)
+	}
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO transfer (copy_id, from_branch_id, to_branch_id, hold_id, status, requested_at) `+
+		`VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		transfer.CopyID, transfer.FromBranchID, transfer.ToBranchID, transfer.HoldID, transfer.Status, transfer.RequestedAt).Scan(&transfer.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return fmt.Errorf("branch %d: %w", transfer.ToBranchID, domain.ErrNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE copy SET status = $1, updated_at = now() WHERE id = $2`, domain.CopyInTransit, transfer.CopyID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (p *postgresTransferRepository) Ship(ctx context.Context, id int, at time.Time) (domain.Transfer, error) {
+	return getOne(ctx, p.Conn, fmt.Errorf("requested transfer %d: %w", id, domain.ErrConflict),
+		`UPDATE transfer SET status = $1, shipped_at = $2 WHERE id = $3 AND status = $4`+returningTransfer,
+		domain.TransferInTransit, at, id, domain.TransferRequested)
+}
+
+// Receive completes the transfer in transit, the copy belongs to the
+// destination branch and is available there from now on.
+func (p *postgresTransferRepository) Receive(ctx context.Context, id int, at time.Time) (transfer domain.Transfer, err error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	defer rollback(tx, &err)
+
+	transfer, err = getOne(ctx, tx, fmt.Errorf("transfer %d in transit: %w", id, domain.ErrConflict),
+		`UPDATE transfer SET status = $1, received_at = $2 WHERE id = $3 AND status = $4`+returningTransfer,
+		domain.TransferReceived, at, id, domain.TransferInTransit)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE copy SET branch_id = $1, status = $2, updated_at = now() WHERE id = $3`,
+		transfer.ToBranchID, domain.CopyAvailable, transfer.CopyID)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	return transfer, tx.Commit()
+}
+
+// Cancel stops an open transfer. The copy is available at its branch again and
+// the hold the copy was sent for waits for another one.
+func (p *postgresTransferRepository) Cancel(ctx context.Context, id int) (transfer domain.Transfer, err error) {
+	tx, err := p.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	defer rollback(tx, &err)
+
+	transfer, err = getOne(ctx, tx, fmt.Errorf("open transfer %d: %w", id, domain.ErrConflict),
+		`UPDATE transfer SET status = $1 WHERE id = $2 AND status IN ($3, $4)`+returningTransfer,
+		domain.TransferCancelled, id, domain.TransferRequested, domain.TransferInTransit)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE copy SET status = $1, updated_at = now() WHERE id = $2 AND status = $3`,
+		domain.CopyAvailable, transfer.CopyID, domain.CopyInTransit)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	if transfer.HoldID != nil {
+		_, err = tx.ExecContext(ctx, `UPDATE hold SET status = $1, copy_id = NULL WHERE id = $2 AND status = $3`,
+			domain.HoldWaiting, *transfer.HoldID, domain.HoldInTransit)
+		if err != nil {
+			return domain.Transfer{}, err
+		}
+	}
+	return transfer, tx.Commit()
+}
+
+// rollback rolls tx back when the function owning it fails with *err.
+func rollback(tx *sql.Tx, err *error) {
+	if *err == nil {
+		return
+	}
+	if errRollback := tx.Rollback(); errRollback != nil {
+		logrus.Error(errRollback)
+	}
+}
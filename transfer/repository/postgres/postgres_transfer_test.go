@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/library/domain"
+)
+
+var transferColumns = []string{"id", "copy_id", "from_branch_id", "to_branch_id", "hold_id", "status", "requested_at", "shipped_at", "received_at"}
+
+func TestAdd(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT branch_id, status FROM copy WHERE id = $1 FOR UPDATE`)).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"branch_id", "status"}).AddRow(1, "available"))
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO transfer`)).WithArgs(5, 1, 2, nil, domain.TransferRequested, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE copy SET status = $1`)).WithArgs(domain.CopyInTransit, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	transfer := domain.Transfer{CopyID: 5, ToBranchID: 2, Status: domain.TransferRequested, RequestedAt: now}
+	err = NewPostgresTransferRepository(db).Add(context.TODO(), &transfer)
+
+	require.NoError(t, err)
+	assert.Equal(t, 8, transfer.ID)
+	assert.Equal(t, 1, transfer.FromBranchID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddCopyOnLoan(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT branch_id, status FROM copy`)).WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"branch_id", "status"}).AddRow(1, "on_loan"))
+	mock.ExpectRollback()
+
+	err = NewPostgresTransferRepository(db).Add(context.TODO(), &domain.Transfer{CopyID: 5, ToBranchID: 2})
+
+	assert.ErrorIs(t, err, domain.ErrNotAvailable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReceiveMovesCopy(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE transfer SET status = $1, received_at = $2 WHERE id = $3 AND status = $4`)).
+		WithArgs(domain.TransferReceived, now, 8, domain.TransferInTransit).
+		WillReturnRows(sqlmock.NewRows(transferColumns).AddRow(8, 5, 1, 2, nil, "received", now, now, now))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE copy SET branch_id = $1, status = $2`)).WithArgs(2, domain.CopyAvailable, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	transfer, err := NewPostgresTransferRepository(db).Receive(context.TODO(), 8, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.TransferReceived, transfer.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelReturnsHoldToQueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE transfer SET status = $1 WHERE id = $2`)).
+		WillReturnRows(sqlmock.NewRows(transferColumns).AddRow(8, 5, 1, 2, 11, "cancelled", now, nil, nil))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE copy SET status = $1`)).WithArgs(domain.CopyAvailable, 5, domain.CopyInTransit).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE hold SET status = $1, copy_id = NULL`)).WithArgs(domain.HoldWaiting, 11, domain.HoldInTransit).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	transfer, err := NewPostgresTransferRepository(db).Cancel(context.TODO(), 8)
+
+	require.NoError(t, err)
+	assert.Equal(t, 11, *transfer.HoldID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bxcodec/library/domain"
+)
+
+// authorizedTransferUseCase checks the permission of the caller before every
+// operation of the wrapped use case.
+type authorizedTransferUseCase struct {
+	next       domain.TransferUseCase
+	authorizer domain.Authorizer
+}
+
+func NewAuthorizedTransferUseCase(next domain.TransferUseCase, authorizer domain.Authorizer) domain.TransferUseCase {
+	return &authorizedTransferUseCase{next: next, authorizer: authorizer}
+}
+
+func (a *authorizedTransferUseCase) Request(ctx context.Context, transfer *domain.Transfer) error {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteTransfers); err != nil {
+		return err
+	}
+	return a.next.Request(ctx, transfer)
+}
+
+func (a *authorizedTransferUseCase) Ship(ctx context.Context, id int) (domain.Transfer, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteTransfers); err != nil {
+		return domain.Transfer{}, err
+	}
+	return a.next.Ship(ctx, id)
+}
+
+func (a *authorizedTransferUseCase) Receive(ctx context.Context, id int) (domain.Transfer, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteTransfers); err != nil {
+		return domain.Transfer{}, err
+	}
+	return a.next.Receive(ctx, id)
+}
+
+func (a *authorizedTransferUseCase) Cancel(ctx context.Context, id int) (domain.Transfer, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionWriteTransfers); err != nil {
+		return domain.Transfer{}, err
+	}
+	return a.next.Cancel(ctx, id)
+}
+
+func (a *authorizedTransferUseCase) GetById(ctx context.Context, id int) (domain.Transfer, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadTransfers); err != nil {
+		return domain.Transfer{}, err
+	}
+	return a.next.GetById(ctx, id)
+}
+
+func (a *authorizedTransferUseCase) FetchByBranch(ctx context.Context, branchID int) ([]domain.Transfer, error) {
+	if err := a.authorizer.Authorize(ctx, domain.PermissionReadTransfers); err != nil {
+		return nil, err
+	}
+	return a.next.FetchByBranch(ctx, branchID)
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/i18n"
+	"github.com/bxcodec/library/internal/timeout"
+	mb "github.com/bxcodec/library/message_broker"
+	"github.com/bxcodec/library/validation"
+)
+
+type transferUseCase struct {
+	transferRepo  domain.TransferRepository
+	holds         domain.HoldUseCase
+	messageBroker mb.MessageBroker
+	timeouts      timeout.Timeouts
+	validator     *validator.Validate
+	now           func() time.Time
+}
+
+func NewTransferUseCase(t domain.TransferRepository, h domain.HoldUseCase, mb mb.MessageBroker, timeouts timeout.Timeouts) domain.TransferUseCase {
+	return &transferUseCase{
+		transferRepo:  t,
+		holds:         h,
+		messageBroker: mb,
+		timeouts:      timeouts,
+		validator:     validation.Validator(),
+		now:           time.Now,
+	}
+}
+
+// Request sends an available copy to another branch. Transfers requested by
+// hand are not bound to a hold.
+func (t *transferUseCase) Request(c context.Context, transfer *domain.Transfer) error {
+	if err := t.validator.Struct(transfer); err != nil {
+		return err
+	}
+	ctxt, cancel := context.WithTimeout(c, t.timeouts.Of("request"))
+	defer cancel()
+
+	transfer.ID = 0
+	transfer.HoldID = nil
+	transfer.Status = domain.TransferRequested
+	transfer.RequestedAt = t.now().UTC()
+	transfer.ShippedAt = nil
+	transfer.ReceivedAt = nil
+	if err := t.transferRepo.Add(ctxt, transfer); err != nil {
+		return err
+	}
+	t.publishEvent(c, mb.TRANSFER_REQUESTED, *transfer)
+	return nil
+}
+
+func (t *transferUseCase) Ship(c context.Context, id int) (domain.Transfer, error) {
+	ctxt, cancel := context.WithTimeout(c, t.timeouts.Of("ship"))
+	defer cancel()
+
+	if _, err := t.transferRepo.GetById(ctxt, id); err != nil {
+		return domain.Transfer{}, err
+	}
+	transfer, err := t.transferRepo.Ship(ctxt, id, t.now().UTC())
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	t.publishEvent(c, mb.TRANSFER_SHIPPED, transfer)
+	return transfer, nil
+}
+
+// Receive completes the transfer and hands the copy to the hold it was sent
+// for, or to the next hold of its book at the destination branch.
+func (t *transferUseCase) Receive(c context.Context, id int) (domain.Transfer, error) {
+	ctxt, cancel := context.WithTimeout(c, t.timeouts.Of("receive"))
+	defer cancel()
+
+	if _, err := t.transferRepo.GetById(ctxt, id); err != nil {
+		return domain.Transfer{}, err
+	}
+	transfer, err := t.transferRepo.Receive(ctxt, id, t.now().UTC())
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	t.publishEvent(c, mb.TRANSFER_RECEIVED, transfer)
+
+	// the transfer is completed already, the copy stays available if no hold
+	// takes it
+	if err = t.holds.Assign(c, transfer.CopyID); err != nil {
+		log.Println(err.Error())
+	}
+	return transfer, nil
+}
+
+// Cancel stops the transfer. The copy, available again, is offered to the
+// waiting holds, the one it was sent for included, so that the queue of its
+// book moves on.
+func (t *transferUseCase) Cancel(c context.Context, id int) (domain.Transfer, error) {
+	ctxt, cancel := context.WithTimeout(c, t.timeouts.Of("cancel"))
+	defer cancel()
+
+	if _, err := t.transferRepo.GetById(ctxt, id); err != nil {
+		return domain.Transfer{}, err
+	}
+	transfer, err := t.transferRepo.Cancel(ctxt, id)
+	if err != nil {
+		return domain.Transfer{}, err
+	}
+	t.publishEvent(c, mb.TRANSFER_CANCELLED, transfer)
+
+	if err = t.holds.Assign(c, transfer.CopyID); err != nil {
+		log.Println(err.Error())
+	}
+	return transfer, nil
+}
+
+func (t *transferUseCase) GetById(c context.Context, id int) (domain.Transfer, error) {
+	ctxt, cancel := context.WithTimeout(c, t.timeouts.Of("get_by_id"))
+	defer cancel()
+
+	return t.transferRepo.GetById(ctxt, id)
+}
+
+func (t *transferUseCase) FetchByBranch(c context.Context, branchID int) ([]domain.Transfer, error) {
+	ctxt, cancel := context.WithTimeout(c, t.timeouts.Of("fetch_by_branch"))
+	defer cancel()
+
+	return t.transferRepo.FetchByBranch(ctxt, branchID)
+}
+
+// publishEvent sends the transfer as the content of an event of eventType. The
+// transfer is already stored, so a failure is only logged.
+func (t *transferUseCase) publishEvent(ctx context.Context, eventType mb.EventType, transfer domain.Transfer) {
+	content, err := json.Marshal(transfer)
+	if err != nil {
+		log.Println(err.Error())
+		return
+	}
+	event := mb.Event{Content: string(content), Subject: string(eventType), Locale: i18n.Locale(ctx)}
+	if principal, ok := domain.PrincipalFromContext(ctx); ok {
+		event.Actor = principal.Subject
+	}
+	event.Tenant, _ = domain.TenantFromContext(ctx)
+	if err = t.messageBroker.Send(event); err != nil {
+		log.Println(err.Error())
+	}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/internal/timeout"
+	mb "github.com/bxcodec/library/message_broker"
+	"github.com/bxcodec/library/mocks"
+)
+
+func TestRequest(t *testing.T) {
+	now := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	mockRepo := new(mocks.TransferRepository)
+	mockBroker := new(mocks.MessageBroker)
+	mockRepo.On("Add", mock.Anything, mock.MatchedBy(func(t *domain.Transfer) bool {
+		return t.Status == domain.TransferRequested && t.RequestedAt.Equal(now) && t.HoldID == nil
+	})).Return(nil).Once()
+	mockBroker.On("Send", mock.MatchedBy(func(e mb.Event) bool { return e.Subject == string(mb.TRANSFER_REQUESTED) })).Return(nil).Once()
+
+	u := NewTransferUseCase(mockRepo, new(mocks.HoldUseCase), mockBroker, timeout.Every(time.Second)).(*transferUseCase)
+	u.now = func() time.Time { return now }
+	holdID := 11
+	err := u.Request(context.TODO(), &domain.Transfer{CopyID: 5, ToBranchID: 2, HoldID: &holdID})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockBroker.AssertExpectations(t)
+}
+
+func TestReceiveAssignsHold(t *testing.T) {
+	mockRepo := new(mocks.TransferRepository)
+	mockHolds := new(mocks.HoldUseCase)
+	mockBroker := new(mocks.MessageBroker)
+	mockRepo.On("GetById", mock.Anything, 8).Return(domain.Transfer{ID: 8, Status: domain.TransferInTransit}, nil).Once()
+	mockRepo.On("Receive", mock.Anything, 8, mock.Anything).Return(domain.Transfer{ID: 8, CopyID: 5, Status: domain.TransferReceived}, nil).Once()
+	mockBroker.On("Send", mock.MatchedBy(func(e mb.Event) bool { return e.Subject == string(mb.TRANSFER_RECEIVED) })).Return(nil).Once()
+	mockHolds.On("Assign", mock.Anything, 5).Return(nil).Once()
+
+	transfer, err := NewTransferUseCase(mockRepo, mockHolds, mockBroker, timeout.Every(time.Second)).Receive(context.TODO(), 8)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.TransferReceived, transfer.Status)
+	mockHolds.AssertExpectations(t)
+}
+
+func TestCancelHoldTransfer(t *testing.T) {
+	holdID := 11
+	mockRepo := new(mocks.TransferRepository)
+	mockHolds := new(mocks.HoldUseCase)
+	mockBroker := new(mocks.MessageBroker)
+	mockRepo.On("GetById", mock.Anything, 8).Return(domain.Transfer{ID: 8}, nil).Once()
+	mockRepo.On("Cancel", mock.Anything, 8).Return(domain.Transfer{ID: 8, CopyID: 5, HoldID: &holdID, Status: domain.TransferCancelled}, nil).Once()
+	mockBroker.On("Send", mock.Anything).Return(nil).Once()
+	mockHolds.On("Assign", mock.Anything, 5).Return(nil).Once()
+
+	_, err := NewTransferUseCase(mockRepo, mockHolds, mockBroker, timeout.Every(time.Second)).Cancel(context.TODO(), 8)
+
+	require.NoError(t, err)
+	mockHolds.AssertExpectations(t)
+}
+
+func TestShipUnknownTransfer(t *testing.T) {
+	mockRepo := new(mocks.TransferRepository)
+	mockRepo.On("GetById", mock.Anything, 8).Return(domain.Transfer{}, domain.ErrNotFound).Once()
+
+	_, err := NewTransferUseCase(mockRepo, new(mocks.HoldUseCase), new(mocks.MessageBroker), timeout.Every(time.Second)).Ship(context.TODO(), 8)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	mockRepo.AssertNotCalled(t, "Ship", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,42 @@
+package validation
+
+import (
+	ut "github.com/go-playground/universal-translator"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// russianTranslations covers the validation rules used by the domain entities.
+var russianTranslations = map[string]string{
+	"required":   "{0} является обязательным полем",
+	"email":      "{0} должно быть корректным email адресом",
+	"len":        "{0} должно иметь длину {1}",
+	"min":        "{0} должно быть не меньше {1}",
+	"max":        "{0} должно быть не больше {1}",
+	"gt":         "{0} должно быть больше {1}",
+	"gte":        "{0} должно быть больше или равно {1}",
+	"lt":         "{0} должно быть меньше {1}",
+	"lte":        "{0} должно быть меньше или равно {1}",
+	"oneof":      "{0} должно быть одним из [{1}]",
+	"permission": "{0} должно быть известным разрешением",
+}
+
+func registerTranslations(v *validator.Validate, trans ut.Translator, translations map[string]string) error {
+	for tag, text := range translations {
+		text := text
+		err := v.RegisterTranslation(tag, trans,
+			func(ut ut.Translator) error {
+				return ut.Add(tag, text, false)
+			},
+			func(ut ut.Translator, fe validator.FieldError) string {
+				msg, err := ut.T(fe.Tag(), fe.Field(), fe.Param())
+				if err != nil {
+					return fe.(error).Error()
+				}
+				return msg
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
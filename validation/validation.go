@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"gopkg.in/go-playground/validator.v9"
+	en_translations "gopkg.in/go-playground/validator.v9/translations/en"
+
+	"github.com/bxcodec/library/domain"
+	"github.com/bxcodec/library/i18n"
+)
+
+// englishTranslations covers the validation rules registered by the service,
+// the default translations cover the others.
+var englishTranslations = map[string]string{
+	"permission": "{0} must be a known permission",
+}
+
+// validate is shared by every caller, validation failures can only be
+// translated by the validator instance the translations were registered on.
+var validate = newValidator()
+
+// Validator returns the validator that reports struct fields by their JSON
+// names, so validation failures can be matched with the request body by
+// clients, and translates them to every supported locale.
+func Validator() *validator.Validate {
+	return validate
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	if err := v.RegisterValidation("permission", isPermission); err != nil {
+		panic(err)
+	}
+
+	if err := en_translations.RegisterDefaultTranslations(v, i18n.Translator(i18n.English)); err != nil {
+		panic(err)
+	}
+	if err := registerTranslations(v, i18n.Translator(i18n.English), englishTranslations); err != nil {
+		panic(err)
+	}
+	if err := registerTranslations(v, i18n.Translator(i18n.Russian), russianTranslations); err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// isPermission validates the name of a permission the rbac policy can grant.
+func isPermission(fl validator.FieldLevel) bool {
+	for _, permission := range domain.Permissions {
+		if fl.Field().String() == string(permission) {
+			return true
+		}
+	}
+	return false
+}